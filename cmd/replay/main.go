@@ -0,0 +1,84 @@
+// replay plays back replay files without a window and checks that the player
+// ends up where it did when they were recorded. It's used to catch changes
+// that break the physics.
+//
+//	go run . -record walk.rpl
+//	go run ./cmd/replay walk.rpl
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/game"
+	"github.com/damienfamed75/rayrem/pkg/replay"
+
+	"github.com/markbates/pkger"
+)
+
+var (
+	tolerance = flag.Float64("tolerance", 0.01, "how far the player can be from where it was recorded")
+	force     = flag.Bool("force", false, "play replays that were recorded with a different config")
+)
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: replay [flags] file...")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	pkger.Include("/assets/")
+	pkger.Include("/config/")
+	pkger.Include("/levels/")
+
+	// Nothing can be loaded into raylib without a window.
+	common.Headless = true
+
+	if err := common.LoadConfig(); err != nil {
+		log.Fatalf("config: %v", err)
+	}
+
+	failed := 0
+	for _, path := range flag.Args() {
+		if err := check(path); err != nil {
+			fmt.Printf("FAIL %s: %v\n", path, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d of %d replays failed\n", failed, flag.NArg())
+		os.Exit(1)
+	}
+}
+
+// check plays back the replay and returns an error if the player didn't end
+// up where it was recorded.
+func check(path string) error {
+	rp, err := replay.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if err := rp.Check(); err != nil && !*force {
+		return err
+	}
+
+	pos := game.PlayReplay(rp)
+	if dist := pos.Subtract(rp.Final).Length(); float64(dist) > *tolerance {
+		return fmt.Errorf("player ended at (%.2f, %.2f) but was recorded at (%.2f, %.2f)",
+			pos.X, pos.Y, rp.Final.X, rp.Final.Y)
+	}
+
+	fmt.Printf("ok   %s: %d ticks (%.1fs), player at (%.2f, %.2f)\n",
+		path, len(rp.Frames), rp.Duration(), pos.X, pos.Y)
+
+	return nil
+}
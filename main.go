@@ -5,19 +5,31 @@ package main
 // here in the root.
 
 import (
+	"flag"
 	"log"
+	"time"
 
 	"github.com/damienfamed75/rayrem/pkg/common"
 	"github.com/damienfamed75/rayrem/pkg/game"
+	"github.com/damienfamed75/rayrem/pkg/input"
+	"github.com/damienfamed75/rayrem/pkg/replay"
 
 	r "github.com/lachee/raylib-goplus/raylib"
 	"github.com/markbates/pkger"
 )
 
+var (
+	recordPath = flag.String("record", "", "record the game's input to a replay file")
+	replayPath = flag.String("replay", "", "play back a replay file instead of reading input")
+)
+
 func main() {
+	flag.Parse()
+
 	// Package the assets and config files into the binary.
 	pkger.Include("/assets/")
 	pkger.Include("/config/")
+	pkger.Include("/levels/")
 
 	// Load config files.
 	err := common.LoadConfig()
@@ -45,15 +57,53 @@ func main() {
 	// Set the master volume to what the settings say.
 	r.SetMasterVolume(float32(common.PublicConfig.GetFloat64("volume.master")))
 
+	// Gameplay randomness is seeded so that it can be replayed.
+	common.SeedRandom(time.Now().UnixNano())
+
+	var playback *replay.Replay
+	if *replayPath != "" {
+		playback, err = replay.Load(*replayPath)
+		if err != nil {
+			log.Fatalf("replay: %v", err)
+		}
+
+		if err := playback.Check(); err != nil {
+			log.Printf("replay might not play back the same: %v", err)
+		}
+
+		common.SeedRandom(playback.Seed)
+	}
+
 	// Create a new game structure.
 	g := game.NewGame()
 	defer g.Unload()
 
-	// Set the default scene to the main menu.
-	g.SetScene(common.ModeMainMenu)
+	switch {
+	case playback != nil:
+		input.Actions.SetSource(replay.NewPlayback(playback))
+		g.SetScene(playback.Mode)
+	case *recordPath != "":
+		// Recordings skip the menu, since it's used with the mouse.
+		g.SetScene(common.ModeTesting)
+
+		rec := replay.NewRecorder(common.ModeTesting)
+		input.Actions.OnUpdate = rec.Record
+
+		defer func() {
+			rp := rec.Finish(g.Player().Rigidbody.Position())
+			if err := rp.Save(*recordPath); err != nil {
+				log.Printf("replay: %v", err)
+			}
+		}()
+	default:
+		// Set the default scene to the main menu. Replays start in their own
+		// scene instead, since going from the menu would add a transition.
+		g.SetScene(common.ModeMainMenu)
+	}
 
 	for !r.WindowShouldClose() {
-		// Update the game's current scene.
+		// Update the game's current scene. The game runs as many fixed
+		// updates as it needs to catch up to the time this frame took.
 		g.Update(r.GetFrameTime())
 
 		r.BeginDrawing()
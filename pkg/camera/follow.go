@@ -15,11 +15,14 @@ import (
 type FollowCamera struct {
 	LerpAmount float32
 	r.Camera2D
+
+	// previous is the camera before the last update.
+	previous r.Camera2D
 }
 
 // NewFollow creates a default offset of the player's position.
 func NewFollow(playerColl *physics.Space) *FollowCamera {
-	return &FollowCamera{
+	c := &FollowCamera{
 		Camera2D: r.Camera2D{
 			// Center the camera on the player's position.
 			Offset: r.NewVector2(
@@ -31,10 +34,15 @@ func NewFollow(playerColl *physics.Space) *FollowCamera {
 		},
 		LerpAmount: common.Config.Camera.Lerp,
 	}
+	c.previous = c.Camera2D
+
+	return c
 }
 
 // Update changes the offset position of the camera and the target.
-func (e *FollowCamera) Update(curr r.Vector2) {
+func (e *FollowCamera) Update(curr r.Vector2, dt float32) {
+	e.previous = e.Camera2D
+
 	// Update camera offset coordinates for it to move.
 	xOff, yOff := -float32(curr.X+4)*e.Zoom, -float32(curr.Y+8)*e.Zoom
 	e.Offset = r.NewVector2(
@@ -44,5 +52,15 @@ func (e *FollowCamera) Update(curr r.Vector2) {
 
 	// Reset the camera's target to the player's current position.
 	// Using a lerp to make the camera movement smoother.
-	e.Target = e.Target.Lerp(curr, e.LerpAmount)
+	e.Target = e.Target.Lerp(curr, stepLerp(e.LerpAmount, dt))
+}
+
+// Interpolate returns the camera in between its last two updates, where alpha
+// is 0 for the previous update and 1 for the current one.
+func (e *FollowCamera) Interpolate(alpha float32) r.Camera2D {
+	c := e.Camera2D
+	c.Offset = e.previous.Offset.Lerp(e.Offset, alpha)
+	c.Target = e.previous.Target.Lerp(e.Target, alpha)
+
+	return c
 }
@@ -0,0 +1,14 @@
+package camera
+
+import "math"
+
+// lerpRate is the amount of updates per second that the lerp amounts in the
+// config are tuned for.
+const lerpRate = 60
+
+// stepLerp converts a lerp amount tuned for 60 updates per second into one for
+// a single update of dt seconds, so cameras follow at the same speed no matter
+// what the tick rate is.
+func stepLerp(amount, dt float32) float32 {
+	return 1 - float32(math.Pow(float64(1-amount), float64(dt*lerpRate)))
+}
@@ -19,3 +19,8 @@ func NewStatic(offset r.Vector2, zoom float32) *StaticCamera {
 		},
 	}
 }
+
+// Interpolate just returns the camera since it never moves.
+func (s *StaticCamera) Interpolate(alpha float32) r.Camera2D {
+	return s.Camera2D
+}
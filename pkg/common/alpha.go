@@ -0,0 +1,6 @@
+package common
+
+// Alpha is how far the game is between the previous and the current fixed
+// update, from 0 to 1. The game loop sets this before drawing so anything that
+// moves can be drawn smoothly in between updates.
+var Alpha float32 = 1
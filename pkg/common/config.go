@@ -1,6 +1,7 @@
 package common
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -18,18 +19,50 @@ var (
 	PublicConfig = viper.New()
 
 	usingEncrypted bool
+
+	configChecks []func() error
 )
 
 type configuration struct {
 	Game struct {
 		Gravity     float32 `json:"gravity"`
 		EntityScale float32 `json:"entityScale"`
+		TickRate    int     `json:"tickRate"`
 	} `json:"game"`
 	Player struct {
-		Spritesheet string  `json:"spritesheet"`
-		Friction    float32 `json:"friction"`
-		JumpHeight  float32 `json:"jumpHeight"`
-		MaxSpeed    struct {
+		Spritesheet  string  `json:"spritesheet"`
+		Acceleration float32 `json:"acceleration"`
+		JumpHeight   float32 `json:"jumpHeight"`
+		// CoyoteTime is how many seconds the player can still jump for after
+		// walking off of a ledge.
+		CoyoteTime float32 `json:"coyoteTime"`
+		// JumpBuffer is how many seconds a jump pressed too early is kept
+		// for, so it happens as soon as the player lands.
+		JumpBuffer float32 `json:"jumpBuffer"`
+		// JumpCutoff is multiplied with the upward speed when the jump button
+		// is let go of early, which makes short hops possible.
+		JumpCutoff float32 `json:"jumpCutoff"`
+		// HurtTime is how many seconds the player is stunned for when hurt.
+		HurtTime float32 `json:"hurtTime"`
+		// Knockback is how hard the player is pushed away from whatever hurt
+		// them.
+		Knockback float32 `json:"knockback"`
+		// AirJumps is how many times the player can jump in the air.
+		AirJumps  int `json:"airJumps"`
+		WallSlide struct {
+			// Gravity is multiplied with the game's gravity while sliding.
+			Gravity float32 `json:"gravity"`
+			// MaxSpeed is the fastest the player can slide down a wall.
+			MaxSpeed float32 `json:"maxSpeed"`
+		} `json:"wallSlide"`
+		WallJump struct {
+			X float32 `json:"X"`
+			Y float32 `json:"Y"`
+			// Lock is how many seconds the player can't move after a wall
+			// jump, so holding towards the wall doesn't cancel the jump.
+			Lock float32 `json:"lock"`
+		} `json:"wallJump"`
+		MaxSpeed struct {
 			X float32 `json:"X"`
 			Y float32 `json:"Y"`
 		} `json:"maxSpeed"`
@@ -41,6 +74,37 @@ type configuration struct {
 	Objects struct {
 		KeyPath string `json:"keyPath"`
 	} `json:"objects"`
+	Physics struct {
+		// Layers are the names of the collision layers in bit order.
+		Layers []string `json:"layers"`
+		// Materials are the surfaces that shapes can be made of by name.
+		// The "default" material is used when a shape doesn't have one.
+		Materials map[string]struct {
+			Friction     float32 `json:"friction"`
+			Restitution  float32 `json:"restitution"`
+			Acceleration float32 `json:"acceleration"`
+			Surface      string  `json:"surface"`
+		} `json:"materials"`
+	} `json:"physics"`
+	Transitions struct {
+		// Scene covers changing scenes and Room covers the player going from
+		// one level of a world to another.
+		Scene TransitionConfig `json:"scene"`
+		Room  TransitionConfig `json:"room"`
+	} `json:"transitions"`
+}
+
+// TransitionConfig is how a transition covers the screen.
+type TransitionConfig struct {
+	// Effect is "fade", "wipe" or "iris".
+	Effect string `json:"effect"`
+	// Duration is how many seconds covering and uncovering each take.
+	Duration float32 `json:"duration"`
+	// Ease is the name of an easing function, like "inOutSine".
+	Ease string `json:"ease"`
+	// ProgressDelay is how many seconds the work done while the screen is
+	// covered can take before its progress is shown.
+	ProgressDelay float32 `json:"progressDelay"`
 }
 
 // LoadConfig loads in the debug and public configuration files.
@@ -49,16 +113,37 @@ func LoadConfig() error {
 		return fmt.Errorf("debug: %w", err)
 	}
 
+	for _, check := range configChecks {
+		if err := check(); err != nil {
+			return fmt.Errorf("debug: %w", err)
+		}
+	}
+
 	if err := loadPublic(); err != nil {
 		return fmt.Errorf("public: %w", err)
 	}
 
 	// update controls.
-	loadControls()
+	if err := loadControls(); err != nil {
+		return fmt.Errorf("controls: %w", err)
+	}
 
 	return nil
 }
 
+// RegisterConfigCheck makes LoadConfig fail with the error of check when the
+// debug config doesn't agree with the package that registered it.
+func RegisterConfigCheck(check func() error) {
+	configChecks = append(configChecks, check)
+}
+
+// ConfigHash returns a hash of the debug config. Replays are only the same
+// when they're played with the same config that they were recorded with.
+func ConfigHash() [sha256.Size]byte {
+	raw, _ := json.Marshal(Config)
+	return sha256.Sum256(raw)
+}
+
 func loadDebug() error {
 	var cfgRaw []byte
 
@@ -106,4 +191,9 @@ func setDefaults() {
 	PublicConfig.SetDefault("volume.music", 1.0)
 	PublicConfig.SetDefault("volume.sound", 1.0)
 	PublicConfig.SetDefault("volume.master", 1.0)
+	// Controls that were added after the first release need defaults so older
+	// config files still work. They're written to the file on the next save.
+	for _, a := range []string{"down", "confirm", "back", "pause"} {
+		PublicConfig.SetDefault("controls."+a, DefaultControls()[a].String())
+	}
 }
@@ -3,6 +3,7 @@ package common
 import (
 	"crypto/rand"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -14,8 +15,12 @@ import (
 // SavePublicConfig unmarshals the viper config into a map then remarshals it
 // into bytes, encrypts the data using salt encryption and then overwrites the
 // current game.config or if you're not using game.config then it'll overwrite
-// the game.json file instead.
-func SavePublicConfig() {
+// the game.json file instead. Controls are always saved by their names.
+func SavePublicConfig() error {
+	if err := nameControls(); err != nil {
+		return fmt.Errorf("controls: %w", err)
+	}
+
 	// if the game config is encrypted then save it encrypted.
 	if usingEncrypted {
 		tmp := make(map[string]interface{})
@@ -24,11 +29,13 @@ func SavePublicConfig() {
 		writeEncrypted(tmp)
 	} else {
 		// non encrypted save.
-		PublicConfig.WriteConfig()
+		if err := PublicConfig.WriteConfig(); err != nil {
+			return fmt.Errorf("write config: %w", err)
+		}
 	}
 
 	// Update the controls structure.
-	loadControls()
+	return loadControls()
 }
 
 func loadTemp(path string) ([]byte, error) {
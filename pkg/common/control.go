@@ -1,29 +1,130 @@
 package common
 
 import (
+	"fmt"
+
 	r "github.com/lachee/raylib-goplus/raylib"
 )
 
 var (
-	// Controls stores all the game controls in raylib Keys for easy use.
+	// Controls stores all the game controls for easy use.
 	Controls controls
 )
 
+// ControlActions are the names of the controls in the public config file in
+// the order that they're shown in the settings.
+var ControlActions = []string{
+	"left", "right", "down", "jump", "shoot", "interact",
+	"confirm", "back", "pause",
+}
+
 type controls struct {
-	Left     r.Key
-	Right    r.Key
-	Jump     r.Key
-	Shoot    r.Key
-	Interact r.Key
+	Left     Control
+	Right    Control
+	Down     Control
+	Jump     Control
+	Shoot    Control
+	Interact Control
+
+	// Confirm and Back move through menus, and Pause opens the pause menu.
+	Confirm Control
+	Back    Control
+	Pause   Control
+}
+
+// field returns the control with the name given.
+func (c *controls) field(action string) (*Control, bool) {
+	switch action {
+	case "left":
+		return &c.Left, true
+	case "right":
+		return &c.Right, true
+	case "down":
+		return &c.Down, true
+	case "jump":
+		return &c.Jump, true
+	case "shoot":
+		return &c.Shoot, true
+	case "interact":
+		return &c.Interact, true
+	case "confirm":
+		return &c.Confirm, true
+	case "back":
+		return &c.Back, true
+	case "pause":
+		return &c.Pause, true
+	}
+
+	return nil, false
+}
+
+// Get returns the control with the name given, such as "jump".
+func (c controls) Get(action string) (Control, bool) {
+	f, ok := c.field(action)
+	if !ok {
+		return Control{}, false
+	}
+
+	return *f, true
+}
+
+// DefaultControls returns the controls that the game comes with.
+func DefaultControls() map[string]Control {
+	return map[string]Control{
+		"left":     KeyControl(r.KeyLeft),
+		"right":    KeyControl(r.KeyRight),
+		"down":     KeyControl(r.KeyDown),
+		"jump":     KeyControl(r.KeyUp),
+		"shoot":    KeyControl(r.KeyC),
+		"interact": KeyControl(r.KeyE),
+		"confirm":  KeyControl(r.KeyEnter),
+		"back":     KeyControl(r.KeyBackspace),
+		"pause":    KeyControl(r.KeyP),
+	}
+}
+
+// SetControl changes a control in the public config file. The change isn't
+// used until the config is saved.
+func SetControl(action string, c Control) error {
+	if _, ok := Controls.field(action); !ok {
+		return fmt.Errorf("unknown control: %q", action)
+	}
+
+	PublicConfig.Set("controls."+action, c.String())
+
+	return nil
 }
 
 // loadControls is used after loading the public config file.
-func loadControls() {
-	Controls = controls{
-		Left:     r.Key(PublicConfig.GetInt32("controls.left")),
-		Right:    r.Key(PublicConfig.GetInt32("controls.right")),
-		Jump:     r.Key(PublicConfig.GetInt32("controls.jump")),
-		Shoot:    r.Key(PublicConfig.GetInt32("controls.shoot")),
-		Interact: r.Key(PublicConfig.GetInt32("controls.interact")),
+func loadControls() error {
+	for _, a := range ControlActions {
+		key := "controls." + a
+
+		control, err := ParseControl(PublicConfig.Get(key))
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		f, _ := Controls.field(a)
+		*f = control
 	}
+
+	return nil
+}
+
+// nameControls rewrites the controls in the public config file by name, so
+// key codes from older config files are saved as names.
+func nameControls() error {
+	for _, a := range ControlActions {
+		key := "controls." + a
+
+		control, err := ParseControl(PublicConfig.Get(key))
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		PublicConfig.Set(key, control.String())
+	}
+
+	return nil
 }
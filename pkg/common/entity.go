@@ -17,26 +17,56 @@ type BasicEntity struct {
 	Scale    float32        // Set in config
 }
 
+// SpriteLoader loads spritesheets and the textures of their images. Entities
+// are made with one so that they can be made without raylib, like in tests.
+type SpriteLoader interface {
+	Spritesheet(fileName string) (*aseprite.File, error)
+	Texture(ase *aseprite.File) (r.Texture2D, error)
+}
+
+// Assets loads sprites from the packaged assets into raylib.
+var Assets SpriteLoader = assetLoader{}
+
+type assetLoader struct{}
+
+// Spritesheet loads a spritesheet from the assets.
+func (assetLoader) Spritesheet(fileName string) (*aseprite.File, error) {
+	return LoadSpritesheet(fileName)
+}
+
+// Texture loads the spritesheet's image from the assets into raylib.
+func (assetLoader) Texture(ase *aseprite.File) (r.Texture2D, error) {
+	// Load the spritesheet image from package.
+	sprite, err := LoadPNG(ase.Meta.Image)
+	if err != nil {
+		return r.Texture2D{}, fmt.Errorf("loading spritesheet image: %w", err)
+	}
+
+	// Load a texture into raylib using a Go image.Image.
+	return LoadTexture(sprite), nil
+}
+
 // NewBasicEntity creates a very basic drawable sprite sheet. The animation
 // state has to be provided that will be default when the sheet will be loaded.
 func NewBasicEntity(ase *aseprite.File) (*BasicEntity, error) {
+	return NewBasicEntityWith(ase, Assets)
+}
+
+// NewBasicEntityWith creates a basic entity whose texture is loaded by the
+// sprite loader given.
+func NewBasicEntityWith(ase *aseprite.File, sprites SpriteLoader) (*BasicEntity, error) {
 	b := &BasicEntity{
 		Ase:   ase,
 		Color: r.White,
 		Scale: Config.Game.EntityScale,
 	}
 
-	// Load the spritesheet image from package.
-	sprite, err := LoadPNG(ase.Meta.Image)
+	var err error
+	b.Sprite, err = sprites.Texture(ase)
 	if err != nil {
-		return nil, fmt.Errorf("loading spritesheet image: %w", err)
+		return nil, err
 	}
 
-	// Load the image into raylib using a Go image.Image.
-	img := r.LoadImageFromGo(sprite)
-	// Load a texture from a raylib image.
-	b.Sprite = r.LoadTextureFromImage(img)
-
 	return b, nil
 }
 
@@ -44,3 +74,8 @@ func NewBasicEntity(ase *aseprite.File) (*BasicEntity, error) {
 func (b *BasicEntity) Update(dt float32) {
 	b.Ase.Update(dt)
 }
+
+// Unload frees the spritesheet's texture.
+func (b *BasicEntity) Unload() {
+	UnloadTexture(b.Sprite)
+}
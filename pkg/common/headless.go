@@ -0,0 +1,31 @@
+package common
+
+import (
+	"image"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Headless is true when the game is running without a window, such as when
+// replays are being checked. Nothing is loaded into raylib while it's set.
+var Headless bool
+
+// LoadTexture loads an image into raylib. Without a window the texture is
+// only the size of the image so things can still be measured by it.
+func LoadTexture(img image.Image) r.Texture2D {
+	if Headless {
+		b := img.Bounds()
+		return r.Texture2D{Width: int32(b.Dx()), Height: int32(b.Dy())}
+	}
+
+	return r.LoadTextureFromGo(img)
+}
+
+// UnloadTexture frees a texture that was loaded with LoadTexture.
+func UnloadTexture(tex r.Texture2D) {
+	if Headless || tex.Id == 0 {
+		return
+	}
+
+	r.UnloadTexture(tex)
+}
@@ -25,21 +25,44 @@ type BasicObject interface {
 
 // Entity is something interacteable such as the player, enemies, or bosses.
 type Entity interface {
-	TakeDamage()
+	// TakeDamage hurts the entity with the damage coming from the point given.
+	TakeDamage(from r.Vector2)
 	Position() r.Vector2
 
 	BasicObject
 }
 
 // SceneManager is an object that has the power to change the current scene.
+// The scenes are a stack, where only the top scene gets input.
 type SceneManager interface {
+	// SetScene replaces every scene with the scene of the mode.
 	SetScene(Mode)
+	// Push adds a scene on top and Pop removes the top scene.
+	Push(Mode)
+	Pop()
+	// Replace swaps the top scene with the scene of the mode.
+	Replace(Mode)
 }
 
 // Scene is a current instance in which the game is running. It can cause
 // updates, draws, and scene changes with permission from the scene manager.
+//
+// Enter is called when the scene is added to the scene manager and Exit when
+// it's removed, but not when other scenes are pushed on top of it. Unload
+// frees everything that the scene loaded after it's exited.
 type Scene interface {
+	Enter()
 	Update(dt float32)
 	Draw()
+	Exit()
 	Unload()
 }
+
+// Overlay is a scene that's shown on top of the scenes under it, like a pause
+// menu. Scenes that aren't overlays cover the scenes under them completely.
+type Overlay interface {
+	// UpdateBelow returns if the scenes under the overlay keep updating.
+	UpdateBelow() bool
+	// DrawBelow returns if the scenes under the overlay are drawn.
+	DrawBelow() bool
+}
@@ -0,0 +1,247 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Control is a single keyboard key or gamepad button.
+type Control struct {
+	Key    r.Key
+	Button r.GamepadButton
+	// Gamepad is true when the control is a gamepad button instead of a key.
+	Gamepad bool
+}
+
+// KeyControl returns a control for a keyboard key.
+func KeyControl(key r.Key) Control {
+	return Control{Key: key}
+}
+
+// ButtonControl returns a control for a gamepad button.
+func ButtonControl(button r.GamepadButton) Control {
+	return Control{Button: button, Gamepad: true}
+}
+
+// String returns the name of the control that's used in game.json.
+func (c Control) String() string {
+	if name, ok := controlNames[c]; ok {
+		return name
+	}
+
+	// Keys that aren't in the table are still written as their key code.
+	return strconv.Itoa(int(c.Key))
+}
+
+// keyNames is every control that can be written by name in game.json.
+var keyNames = []struct {
+	name    string
+	control Control
+}{
+	{"A", KeyControl(r.KeyA)},
+	{"B", KeyControl(r.KeyB)},
+	{"C", KeyControl(r.KeyC)},
+	{"D", KeyControl(r.KeyD)},
+	{"E", KeyControl(r.KeyE)},
+	{"F", KeyControl(r.KeyF)},
+	{"G", KeyControl(r.KeyG)},
+	{"H", KeyControl(r.KeyH)},
+	{"I", KeyControl(r.KeyI)},
+	{"J", KeyControl(r.KeyJ)},
+	{"K", KeyControl(r.KeyK)},
+	{"L", KeyControl(r.KeyL)},
+	{"M", KeyControl(r.KeyM)},
+	{"N", KeyControl(r.KeyN)},
+	{"O", KeyControl(r.KeyO)},
+	{"P", KeyControl(r.KeyP)},
+	{"Q", KeyControl(r.KeyQ)},
+	{"R", KeyControl(r.KeyR)},
+	{"S", KeyControl(r.KeyS)},
+	{"T", KeyControl(r.KeyT)},
+	{"U", KeyControl(r.KeyU)},
+	{"V", KeyControl(r.KeyV)},
+	{"W", KeyControl(r.KeyW)},
+	{"X", KeyControl(r.KeyX)},
+	{"Y", KeyControl(r.KeyY)},
+	{"Z", KeyControl(r.KeyZ)},
+
+	{"0", KeyControl(r.KeyZero)},
+	{"1", KeyControl(r.KeyOne)},
+	{"2", KeyControl(r.KeyTwo)},
+	{"3", KeyControl(r.KeyThree)},
+	{"4", KeyControl(r.KeyFour)},
+	{"5", KeyControl(r.KeyFive)},
+	{"6", KeyControl(r.KeySix)},
+	{"7", KeyControl(r.KeySeven)},
+	{"8", KeyControl(r.KeyEight)},
+	{"9", KeyControl(r.KeyNine)},
+
+	{"APOSTROPHE", KeyControl(r.KeyApostrophe)},
+	{"COMMA", KeyControl(r.KeyComma)},
+	{"MINUS", KeyControl(r.KeyMinus)},
+	{"PERIOD", KeyControl(r.KeyPeriod)},
+	{"SLASH", KeyControl(r.KeySlash)},
+	{"SEMICOLON", KeyControl(r.KeySemicolon)},
+	{"EQUAL", KeyControl(r.KeyEqual)},
+	{"LEFT_BRACKET", KeyControl(r.KeyLeftBracket)},
+	{"BACKSLASH", KeyControl(r.KeyBackslash)},
+	{"RIGHT_BRACKET", KeyControl(r.KeyRightBracket)},
+	{"GRAVE", KeyControl(r.KeyGrave)},
+
+	{"SPACE", KeyControl(r.KeySpace)},
+	{"ESCAPE", KeyControl(r.KeyEscape)},
+	{"ENTER", KeyControl(r.KeyEnter)},
+	{"TAB", KeyControl(r.KeyTab)},
+	{"BACKSPACE", KeyControl(r.KeyBackspace)},
+	{"INSERT", KeyControl(r.KeyInsert)},
+	{"DELETE", KeyControl(r.KeyDelete)},
+	{"RIGHT", KeyControl(r.KeyRight)},
+	{"LEFT", KeyControl(r.KeyLeft)},
+	{"DOWN", KeyControl(r.KeyDown)},
+	{"UP", KeyControl(r.KeyUp)},
+	{"PAGE_UP", KeyControl(r.KeyPageUp)},
+	{"PAGE_DOWN", KeyControl(r.KeyPageDown)},
+	{"HOME", KeyControl(r.KeyHome)},
+	{"END", KeyControl(r.KeyEnd)},
+	{"CAPS_LOCK", KeyControl(r.KeyCapsLock)},
+	{"SCROLL_LOCK", KeyControl(r.KeyScrollLock)},
+	{"NUM_LOCK", KeyControl(r.KeyNumLock)},
+	{"PRINT_SCREEN", KeyControl(r.KeyPrintScreen)},
+	{"PAUSE", KeyControl(r.KeyPause)},
+	{"F1", KeyControl(r.KeyF1)},
+	{"F2", KeyControl(r.KeyF2)},
+	{"F3", KeyControl(r.KeyF3)},
+	{"F4", KeyControl(r.KeyF4)},
+	{"F5", KeyControl(r.KeyF5)},
+	{"F6", KeyControl(r.KeyF6)},
+	{"F7", KeyControl(r.KeyF7)},
+	{"F8", KeyControl(r.KeyF8)},
+	{"F9", KeyControl(r.KeyF9)},
+	{"F10", KeyControl(r.KeyF10)},
+	{"F11", KeyControl(r.KeyF11)},
+	{"F12", KeyControl(r.KeyF12)},
+	{"LEFT_SHIFT", KeyControl(r.KeyLeftShift)},
+	{"LEFT_CONTROL", KeyControl(r.KeyLeftControl)},
+	{"LEFT_ALT", KeyControl(r.KeyLeftAlt)},
+	{"LEFT_SUPER", KeyControl(r.KeyLeftSuper)},
+	{"RIGHT_SHIFT", KeyControl(r.KeyRightShift)},
+	{"RIGHT_CONTROL", KeyControl(r.KeyRightControl)},
+	{"RIGHT_ALT", KeyControl(r.KeyRightAlt)},
+	{"RIGHT_SUPER", KeyControl(r.KeyRightSuper)},
+	{"MENU", KeyControl(r.KeyKbMenu)},
+
+	{"KP_0", KeyControl(r.KeyKp0)},
+	{"KP_1", KeyControl(r.KeyKp1)},
+	{"KP_2", KeyControl(r.KeyKp2)},
+	{"KP_3", KeyControl(r.KeyKp3)},
+	{"KP_4", KeyControl(r.KeyKp4)},
+	{"KP_5", KeyControl(r.KeyKp5)},
+	{"KP_6", KeyControl(r.KeyKp6)},
+	{"KP_7", KeyControl(r.KeyKp7)},
+	{"KP_8", KeyControl(r.KeyKp8)},
+	{"KP_9", KeyControl(r.KeyKp9)},
+	{"KP_DECIMAL", KeyControl(r.KeyKpDecimal)},
+	{"KP_DIVIDE", KeyControl(r.KeyKpDivide)},
+	{"KP_MULTIPLY", KeyControl(r.KeyKpMultiply)},
+	{"KP_SUBTRACT", KeyControl(r.KeyKpSubtract)},
+	{"KP_ADD", KeyControl(r.KeyKpAdd)},
+	{"KP_ENTER", KeyControl(r.KeyKpEnter)},
+	{"KP_EQUAL", KeyControl(r.KeyKpEqual)},
+
+	// Gamepad buttons are named after an Xbox controller.
+	{"GAMEPAD_A", ButtonControl(r.GamepadButtonRightFaceDown)},
+	{"GAMEPAD_B", ButtonControl(r.GamepadButtonRightFaceRight)},
+	{"GAMEPAD_X", ButtonControl(r.GamepadButtonRightFaceLeft)},
+	{"GAMEPAD_Y", ButtonControl(r.GamepadButtonRightFaceUp)},
+	{"GAMEPAD_UP", ButtonControl(r.GamepadButtonLeftFaceUp)},
+	{"GAMEPAD_DOWN", ButtonControl(r.GamepadButtonLeftFaceDown)},
+	{"GAMEPAD_LEFT", ButtonControl(r.GamepadButtonLeftFaceLeft)},
+	{"GAMEPAD_RIGHT", ButtonControl(r.GamepadButtonLeftFaceRight)},
+	{"GAMEPAD_LB", ButtonControl(r.GamepadButtonLeftTrigger1)},
+	{"GAMEPAD_LT", ButtonControl(r.GamepadButtonLeftTrigger2)},
+	{"GAMEPAD_RB", ButtonControl(r.GamepadButtonRightTrigger1)},
+	{"GAMEPAD_RT", ButtonControl(r.GamepadButtonRightTrigger2)},
+	{"GAMEPAD_SELECT", ButtonControl(r.GamepadButtonMiddleLeft)},
+	{"GAMEPAD_GUIDE", ButtonControl(r.GamepadButtonMiddle)},
+	{"GAMEPAD_START", ButtonControl(r.GamepadButtonMiddleRight)},
+	{"GAMEPAD_LS", ButtonControl(r.GamepadButtonLeftThumb)},
+	{"GAMEPAD_RS", ButtonControl(r.GamepadButtonRightThumb)},
+}
+
+// controlsByName and controlNames look up the key name table both ways.
+var (
+	controlsByName = make(map[string]Control, len(keyNames))
+	controlNames   = make(map[Control]string, len(keyNames))
+)
+
+func init() {
+	for _, k := range keyNames {
+		controlsByName[k.name] = k.control
+		controlNames[k.control] = k.name
+	}
+}
+
+// NamedControls returns every control that has a name in the order that
+// they're listed in.
+func NamedControls() []Control {
+	cc := make([]Control, len(keyNames))
+	for i, k := range keyNames {
+		cc[i] = k.control
+	}
+
+	return cc
+}
+
+// ControlNamed returns the control with the name given, such as "SPACE" or
+// "GAMEPAD_A". Names aren't case sensitive.
+func ControlNamed(name string) (Control, bool) {
+	c, ok := controlsByName[strings.ToUpper(strings.TrimSpace(name))]
+	return c, ok
+}
+
+// ParseControl reads a control from a config value. Controls are usually
+// written by name, but older config files used raylib key codes, so numbers
+// are read as key codes.
+func ParseControl(value interface{}) (Control, error) {
+	switch v := value.(type) {
+	case string:
+		if c, ok := ControlNamed(v); ok {
+			return c, nil
+		}
+
+		// Key codes that were saved as text.
+		if code, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return parseKeyCode(code)
+		}
+
+		return Control{}, fmt.Errorf("unknown key name: %q", v)
+	case int:
+		return parseKeyCode(v)
+	case int32:
+		return parseKeyCode(int(v))
+	case int64:
+		return parseKeyCode(int(v))
+	case float64:
+		if v != float64(int(v)) {
+			return Control{}, fmt.Errorf("key code isn't a whole number: %v", v)
+		}
+
+		return parseKeyCode(int(v))
+	case nil:
+		return Control{}, fmt.Errorf("missing key")
+	}
+
+	return Control{}, fmt.Errorf("key must be a name or key code, got %T", value)
+}
+
+// parseKeyCode returns the key with the raylib key code given.
+func parseKeyCode(code int) (Control, error) {
+	if code <= 0 {
+		return Control{}, fmt.Errorf("invalid key code: %d", code)
+	}
+
+	return KeyControl(r.Key(code)), nil
+}
@@ -10,4 +10,7 @@ const (
 	ModeGame
 	ModeTesting
 	ModeGameOver
+	// ModePause and ModeSettings are shown on top of other scenes.
+	ModePause
+	ModeSettings
 )
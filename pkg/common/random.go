@@ -0,0 +1,24 @@
+package common
+
+import (
+	"math/rand"
+)
+
+var (
+	// Random is used for anything random in the game, so replays can play
+	// back the same numbers by using the same seed.
+	Random = rand.New(rand.NewSource(1))
+
+	seed int64 = 1
+)
+
+// SeedRandom restarts Random with the seed given.
+func SeedRandom(s int64) {
+	seed = s
+	Random.Seed(s)
+}
+
+// Seed returns the seed that Random was last started with.
+func Seed() int64 {
+	return seed
+}
@@ -0,0 +1,77 @@
+// Package ease contains easing functions that can be used to make movement
+// speed up or slow down over time instead of moving at a constant speed.
+package ease
+
+import "math"
+
+// Func maps a linear progress from 0 to 1 onto a curve that also goes from
+// 0 to 1.
+type Func func(t float32) float32
+
+// Linear doesn't ease at all.
+func Linear(t float32) float32 {
+	return t
+}
+
+// InQuad starts slow and speeds up.
+func InQuad(t float32) float32 {
+	return t * t
+}
+
+// OutQuad starts fast and slows down.
+func OutQuad(t float32) float32 {
+	return t * (2 - t)
+}
+
+// InOutQuad speeds up until halfway and then slows down.
+func InOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return -1 + (4-2*t)*t
+}
+
+// InCubic starts slow and speeds up quicker than InQuad.
+func InCubic(t float32) float32 {
+	return t * t * t
+}
+
+// OutCubic starts fast and slows down quicker than OutQuad.
+func OutCubic(t float32) float32 {
+	t--
+	return t*t*t + 1
+}
+
+// InOutCubic speeds up until halfway and then slows down.
+func InOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	t = 2*t - 2
+	return 0.5*t*t*t + 1
+}
+
+// InOutSine gently speeds up and slows down following a sine wave.
+func InOutSine(t float32) float32 {
+	return float32(-(math.Cos(math.Pi*float64(t)) - 1) / 2)
+}
+
+// funcs are the easing functions by the names that are used in level files.
+var funcs = map[string]Func{
+	"linear":     Linear,
+	"inQuad":     InQuad,
+	"outQuad":    OutQuad,
+	"inOutQuad":  InOutQuad,
+	"inCubic":    InCubic,
+	"outCubic":   OutCubic,
+	"inOutCubic": InOutCubic,
+	"inOutSine":  InOutSine,
+}
+
+// Named returns the easing function with the name given, such as "inOutSine".
+func Named(name string) (Func, bool) {
+	f, ok := funcs[name]
+	return f, ok
+}
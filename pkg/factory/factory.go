@@ -0,0 +1,188 @@
+// Package factory creates game objects by the name of their type, so that
+// anything that reads objects from data, like level files, can create any
+// kind of object. Packages with objects register them when they're imported.
+package factory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Constructor creates an object from its properties. The object has to be
+// something that a broadphase's InsertI accepts.
+type Constructor func(props Properties) (interface{}, error)
+
+// Resolver is an object that refers to other objects by their ID. Resolve is
+// called once every object has been created, with find for looking up the
+// objects that it refers to.
+type Resolver interface {
+	Resolve(find func(id string) (interface{}, bool)) error
+}
+
+// constructors are the constructors by the type that they create.
+var constructors = make(map[string]Constructor)
+
+// Register sets the constructor of the type given, which replaces the one
+// that was there before.
+func Register(typ string, c Constructor) {
+	constructors[typ] = c
+}
+
+// Has returns if the type has a constructor.
+func Has(typ string) bool {
+	_, ok := constructors[typ]
+	return ok
+}
+
+// Types returns every type that has a constructor in alphabetical order.
+func Types() []string {
+	types := make([]string, 0, len(constructors))
+	for t := range constructors {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// New creates a single object of the type given. References to other objects
+// aren't resolved.
+func New(typ string, props Properties) (interface{}, error) {
+	c, ok := constructors[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown object type %q", typ)
+	}
+
+	o, err := c(props)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", typ, err)
+	}
+
+	return o, nil
+}
+
+// Object is an object that hasn't been created yet.
+type Object struct {
+	Type string
+	// ID is used by other objects to refer to this one, and can be empty.
+	ID         string
+	Properties Properties
+}
+
+// Build creates every object and then resolves their references to each
+// other, so objects can refer to ones that are after them. The objects are
+// returned in the same order.
+func Build(objects []Object) ([]interface{}, error) {
+	built := make([]interface{}, len(objects))
+	ids := make(map[string]interface{})
+
+	for i, o := range objects {
+		obj, err := New(o.Type, o.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("objects[%d]: %w", i, err)
+		}
+
+		if o.ID != "" {
+			if _, ok := ids[o.ID]; ok {
+				return nil, fmt.Errorf("objects[%d]: duplicate id %q", i, o.ID)
+			}
+
+			ids[o.ID] = obj
+		}
+
+		built[i] = obj
+	}
+
+	find := func(id string) (interface{}, bool) {
+		obj, ok := ids[id]
+		return obj, ok
+	}
+
+	for i, obj := range built {
+		if res, ok := obj.(Resolver); ok {
+			if err := res.Resolve(find); err != nil {
+				return nil, fmt.Errorf("objects[%d]: %w", i, err)
+			}
+		}
+	}
+
+	return built, nil
+}
+
+// Properties are the settings of an object by name. Values can be numbers,
+// strings or booleans, and numbers and booleans can be written as strings
+// since some formats only have strings.
+type Properties map[string]interface{}
+
+// String returns a property as a string, or an empty string if it isn't
+// set.
+func (p Properties) String(name string) string {
+	switch v := p[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Float returns a property as a number, or 0 if it isn't set.
+func (p Properties) Float(name string) (float32, error) {
+	switch v := p[name].(type) {
+	case nil:
+		return 0, nil
+	case float32:
+		return v, nil
+	case float64:
+		return float32(v), nil
+	case int:
+		return float32(v), nil
+	case string:
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return 0, fmt.Errorf("property %q: %w", name, err)
+		}
+
+		return float32(f), nil
+	default:
+		return 0, fmt.Errorf("property %q: %v isn't a number", name, v)
+	}
+}
+
+// Bool returns a property as a boolean, or false if it isn't set.
+func (p Properties) Bool(name string) (bool, error) {
+	switch v := p[name].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("property %q: %w", name, err)
+		}
+
+		return b, nil
+	default:
+		return false, fmt.Errorf("property %q: %v isn't a boolean", name, v)
+	}
+}
+
+// Position returns the "x" and "y" properties as a vector.
+func (p Properties) Position() (r.Vector2, error) {
+	x, err := p.Float("x")
+	if err != nil {
+		return r.Vector2{}, err
+	}
+
+	y, err := p.Float("y")
+	if err != nil {
+		return r.Vector2{}, err
+	}
+
+	return r.NewVector2(x, y), nil
+}
@@ -0,0 +1,206 @@
+// Package fsm is a small finite state machine for things like the player and
+// enemies. States have enter, exit and update hooks along with an animation
+// tag that's played when the state is entered, and transitions between states
+// are only taken when their guard allows it.
+package fsm
+
+import (
+	"fmt"
+)
+
+// Animator plays animations by their tag, such as an *aseprite.File.
+type Animator interface {
+	Play(animation string) error
+}
+
+// State is a single state that a machine can be in.
+type State struct {
+	// Name is used to refer to the state in transitions.
+	Name string
+	// Animation is the tag played when the state is entered. If it's empty
+	// then whatever was playing keeps playing.
+	Animation string
+
+	// Enter is called when the machine changes into the state.
+	Enter func()
+	// Exit is called when the machine changes out of the state.
+	Exit func()
+	// Update is called every update that the machine is in the state.
+	Update func(dt float32)
+}
+
+// Transition changes the machine from any of the From states to the To state
+// when the guard returns true. A transition without any From states can be
+// taken from every state except the one it goes to.
+type Transition struct {
+	From  []string
+	To    string
+	Guard func() bool
+}
+
+// from returns if the transition can be taken from the state.
+func (t *Transition) from(state string) bool {
+	if len(t.From) == 0 {
+		return state != t.To
+	}
+
+	for _, f := range t.From {
+		if f == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Machine is a finite state machine.
+type Machine struct {
+	states      map[string]*State
+	transitions []Transition
+
+	current  *State
+	previous string
+	// elapsed is how long the machine has been in the current state.
+	elapsed float32
+
+	animator Animator
+	// OnChange is called after every change of state if it isn't nil.
+	OnChange func(from, to string)
+}
+
+// New returns an empty state machine. The animator may be nil if the states
+// don't need to play any animations.
+func New(animator Animator) *Machine {
+	return &Machine{
+		states:   make(map[string]*State),
+		animator: animator,
+	}
+}
+
+// Add adds states to the machine. A state with the same name as one that was
+// already added replaces it.
+func (m *Machine) Add(states ...*State) {
+	for _, s := range states {
+		m.states[s.Name] = s
+	}
+}
+
+// AddTransition adds transitions to the machine. Transitions are checked in
+// the order that they were added, so the first one allowed is taken.
+func (m *Machine) AddTransition(transitions ...Transition) error {
+	for _, t := range transitions {
+		if _, ok := m.states[t.To]; !ok {
+			return fmt.Errorf("transition to unknown state: %q", t.To)
+		}
+
+		for _, from := range t.From {
+			if _, ok := m.states[from]; !ok {
+				return fmt.Errorf("transition from unknown state: %q", from)
+			}
+		}
+
+		m.transitions = append(m.transitions, t)
+	}
+
+	return nil
+}
+
+// Set changes the machine into the state with the name given, even if the
+// machine is already in it. No guards are checked.
+func (m *Machine) Set(name string) error {
+	next, ok := m.states[name]
+	if !ok {
+		return fmt.Errorf("unknown state: %q", name)
+	}
+
+	var from string
+	if m.current != nil {
+		from = m.current.Name
+
+		if m.current.Exit != nil {
+			m.current.Exit()
+		}
+	}
+
+	m.previous = from
+	m.current = next
+	m.elapsed = 0
+
+	if m.animator != nil && next.Animation != "" {
+		if err := m.animator.Play(next.Animation); err != nil {
+			return fmt.Errorf("play %q: %w", next.Animation, err)
+		}
+	}
+
+	if next.Enter != nil {
+		next.Enter()
+	}
+
+	if m.OnChange != nil {
+		m.OnChange(from, name)
+	}
+
+	return nil
+}
+
+// Update takes the first transition out of the current state that's allowed
+// and then updates whatever state the machine is in.
+func (m *Machine) Update(dt float32) error {
+	if m.current == nil {
+		return fmt.Errorf("machine hasn't been started")
+	}
+
+	for i := range m.transitions {
+		t := &m.transitions[i]
+		if !t.from(m.current.Name) || (t.Guard != nil && !t.Guard()) {
+			continue
+		}
+
+		if err := m.Set(t.To); err != nil {
+			return err
+		}
+
+		break
+	}
+
+	m.elapsed += dt
+
+	if m.current.Update != nil {
+		m.current.Update(dt)
+	}
+
+	return nil
+}
+
+// Current returns the name of the state that the machine is in, or an empty
+// string if the machine hasn't been started.
+func (m *Machine) Current() string {
+	if m.current == nil {
+		return ""
+	}
+
+	return m.current.Name
+}
+
+// Is returns if the machine is in any of the states given.
+func (m *Machine) Is(names ...string) bool {
+	current := m.Current()
+	for _, n := range names {
+		if n == current {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Previous returns the name of the state that the machine was in before the
+// current one.
+func (m *Machine) Previous() string {
+	return m.previous
+}
+
+// Elapsed returns how many seconds the machine has been in the current state.
+func (m *Machine) Elapsed() float32 {
+	return m.elapsed
+}
@@ -0,0 +1,168 @@
+package fsm
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// animator records every animation that's played.
+type animator struct {
+	played []string
+	// missing is an animation that can't be played.
+	missing string
+}
+
+func (a *animator) Play(animation string) error {
+	if animation == a.missing {
+		return fmt.Errorf("no animation %q", animation)
+	}
+
+	a.played = append(a.played, animation)
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	var calls []string
+	hooks := func(name string) *State {
+		return &State{
+			Name:   name,
+			Enter:  func() { calls = append(calls, "enter "+name) },
+			Exit:   func() { calls = append(calls, "exit "+name) },
+			Update: func(dt float32) { calls = append(calls, "update "+name) },
+		}
+	}
+
+	m := New(nil)
+	m.Add(hooks("a"), hooks("b"))
+
+	var changes []string
+	m.OnChange = func(from, to string) {
+		changes = append(changes, from+">"+to)
+	}
+
+	if err := m.AddTransition(Transition{From: []string{"a"}, To: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Set("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Update(0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"enter a", "exit a", "enter b", "update b"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	if want := []string{">a", "a>b"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+
+	if m.Current() != "b" || m.Previous() != "a" {
+		t.Errorf("current = %q and previous = %q, want b and a", m.Current(), m.Previous())
+	}
+
+	if m.Elapsed() != 0.5 {
+		t.Errorf("elapsed = %v, want 0.5", m.Elapsed())
+	}
+}
+
+func TestGuards(t *testing.T) {
+	m := New(nil)
+	m.Add(&State{Name: "idle"}, &State{Name: "run"}, &State{Name: "jump"})
+
+	var running, jumping bool
+	err := m.AddTransition(
+		// Jumping is checked first, so it wins when both are allowed.
+		Transition{To: "jump", Guard: func() bool { return jumping }},
+		Transition{From: []string{"idle"}, To: "run", Guard: func() bool { return running }},
+		Transition{From: []string{"run", "jump"}, To: "idle", Guard: func() bool { return !running && !jumping }},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Set("idle"); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		running, jumping bool
+		want             string
+	}{
+		{false, false, "idle"},
+		{true, false, "run"},
+		{true, false, "run"},
+		{true, true, "jump"},
+		// Transitions without From states aren't taken into their own state.
+		{true, true, "jump"},
+		{false, false, "idle"},
+	}
+
+	for i, s := range steps {
+		running, jumping = s.running, s.jumping
+
+		if err := m.Update(1); err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Current() != s.want {
+			t.Errorf("step %d: state = %q, want %q", i, m.Current(), s.want)
+		}
+	}
+
+	if !m.Is("run", "idle") || m.Is("run", "jump") {
+		t.Errorf("Is doesn't match the current state %q", m.Current())
+	}
+}
+
+func TestAnimations(t *testing.T) {
+	a := &animator{missing: "broken"}
+
+	m := New(a)
+	m.Add(
+		&State{Name: "idle", Animation: "idle"},
+		&State{Name: "attack", Animation: "attack"},
+		// States without an animation keep the last one playing.
+		&State{Name: "stun"},
+		&State{Name: "broken", Animation: "broken"},
+	)
+
+	for _, name := range []string{"idle", "attack", "stun", "idle"} {
+		if err := m.Set(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []string{"idle", "attack", "idle"}; !reflect.DeepEqual(a.played, want) {
+		t.Errorf("played = %v, want %v", a.played, want)
+	}
+
+	if err := m.Set("broken"); err == nil {
+		t.Error("playing a missing animation didn't return an error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	m := New(nil)
+	m.Add(&State{Name: "idle"})
+
+	if err := m.Update(1); err == nil {
+		t.Error("updating a machine that hasn't started didn't return an error")
+	}
+
+	if err := m.Set("missing"); err == nil {
+		t.Error("setting an unknown state didn't return an error")
+	}
+
+	if err := m.AddTransition(Transition{To: "missing"}); err == nil {
+		t.Error("a transition to an unknown state didn't return an error")
+	}
+
+	if err := m.AddTransition(Transition{From: []string{"missing"}, To: "idle"}); err == nil {
+		t.Error("a transition from an unknown state didn't return an error")
+	}
+}
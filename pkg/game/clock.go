@@ -0,0 +1,52 @@
+package game
+
+// maxFrameTime caps how much time a single frame may add to the clock. Without
+// it a long hitch (like dragging the window) would make the game try to catch
+// up on hundreds of updates at once.
+const maxFrameTime = 0.25
+
+// Clock splits up the time between frames into fixed size steps so the game
+// is always updated at the same rate no matter how fast it's drawn.
+type Clock struct {
+	step        float32
+	accumulator float32
+}
+
+// NewClock creates a clock that ticks tickRate times per second.
+// If the tick rate isn't positive then it defaults to 60.
+func NewClock(tickRate int) *Clock {
+	if tickRate <= 0 {
+		tickRate = 60
+	}
+
+	return &Clock{
+		step: 1 / float32(tickRate),
+	}
+}
+
+// Step returns the fixed delta time of a single tick in seconds.
+func (c *Clock) Step() float32 {
+	return c.step
+}
+
+// Advance adds the time that the last frame took and returns how many ticks
+// should be ran to catch up.
+func (c *Clock) Advance(frameTime float32) int {
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
+	}
+	c.accumulator += frameTime
+
+	var ticks int
+	for c.accumulator >= c.step {
+		c.accumulator -= c.step
+		ticks++
+	}
+
+	return ticks
+}
+
+// Alpha returns how far the clock is into the next tick, from 0 to 1.
+func (c *Clock) Alpha() float32 {
+	return c.accumulator / c.step
+}
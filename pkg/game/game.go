@@ -4,9 +4,15 @@ import (
 	"log"
 
 	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+	"github.com/damienfamed75/rayrem/pkg/level"
+	// Registers the LDtk and Tiled map formats.
+	_ "github.com/damienfamed75/rayrem/pkg/level/ldtk"
+	_ "github.com/damienfamed75/rayrem/pkg/level/tiled"
 	"github.com/damienfamed75/rayrem/pkg/physics"
 	"github.com/damienfamed75/rayrem/pkg/player"
 	"github.com/damienfamed75/rayrem/pkg/scene"
+	"github.com/damienfamed75/rayrem/pkg/transition"
 
 	r "github.com/lachee/raylib-goplus/raylib"
 )
@@ -15,64 +21,292 @@ var (
 	_ common.SceneManager = &Game{}
 )
 
-// Game is the scene manager and holder of the player and world.
+// Game is the scene manager and holder of the player.
 type Game struct {
-	mode common.Mode
+	clock *Clock
 
 	player *player.Player
-	solids *physics.SpatialHashmap
 
-	scenes map[common.Mode]common.Scene
+	// scenes create the scenes of the game by their mode. A scene is only
+	// created when it's added, and it's unloaded once it's removed so every
+	// scene starts with a fresh world.
+	scenes map[common.Mode]func() (common.Scene, error)
+	// stack is the scenes that are running with the top scene last, and
+	// changes are the changes to the stack that are made before the next
+	// update.
+	stack   []common.Scene
+	changes []func()
+	// transition covers the screen while the scenes are set or replaced.
+	transition *transition.Transition
 }
 
-// NewGame sets up the game and creates the player and world.
-// Also is used to create all the scenes and assign them in the scenes map.
+// NewGame sets up the game and creates the player.
+// Also is used to assign the functions that create the scenes.
 func NewGame() *Game {
 	g := &Game{
-		solids: physics.NewSpatialHashmap(6),
+		clock: NewClock(common.Config.Game.TickRate),
 	}
 
-	// Create the player.
+	// Bind the actions to the controls from the config.
+	input.Load()
+
+	// Create the player. It isn't in any world until a scene puts it in one.
 	// TODO zones only activate on players.
-	player, err := player.New(0, 0, g.solids)
+	player, err := player.New(0, 0, physics.NewSpatialHashmap(6))
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	g.player = player
 
+	g.transition, err = transition.Load(common.Config.Transitions.Scene)
+	if err != nil {
+		log.Fatalf("scene transition: %v", err)
+	}
+
 	// Setup all the scenes in the game.
-	g.scenes = map[common.Mode]common.Scene{
-		common.ModeTesting:  scene.NewTestingScene(g, g.player, g.solids),
-		common.ModeMainMenu: scene.NewMenu(g),
+	g.scenes = map[common.Mode]func() (common.Scene, error){
+		common.ModeTesting:  g.newTesting,
+		common.ModeMainMenu: func() (common.Scene, error) { return scene.NewMenu(g), nil },
+		common.ModePause:    func() (common.Scene, error) { return scene.NewPause(g), nil },
+		common.ModeSettings: func() (common.Scene, error) { return scene.NewSettings(g), nil },
 	}
 
 	return g
 }
 
-// SetScene changes the scene mode.
+// newTesting creates the testing world.
+func (g *Game) newTesting() (common.Scene, error) {
+	testing, err := level.LoadWorld("testing")
+	if err != nil {
+		return nil, err
+	}
+
+	// Every level of the world gets its own solids so they don't collide
+	// with each other.
+	return scene.NewWorldScene(g, g.player, testing, func() physics.Broadphase {
+		return physics.NewSpatialHashmap(6)
+	})
+}
+
+// SetScene removes every scene and adds the scene of the mode given. Changes
+// to the scenes are made before the next update so the current scene can
+// finish its update or draw.
 func (g *Game) SetScene(mode common.Mode) {
-	// g.world.Clear() // Clear the world of any remaining shapes.
-	g.mode = mode
+	g.transit(mode, func(s common.Scene) {
+		for len(g.stack) > 0 {
+			g.pop()
+		}
+
+		g.push(s)
+	})
+}
+
+// Push adds the scene of the mode given on top of the current scene.
+func (g *Game) Push(mode common.Mode) {
+	g.changes = append(g.changes, func() {
+		if s, ok := g.newScene(mode); ok {
+			g.push(s)
+		}
+	})
+}
+
+// Pop removes the top scene, which leaves the scene underneath it on top. The
+// last scene can't be removed.
+func (g *Game) Pop() {
+	g.changes = append(g.changes, func() {
+		if len(g.stack) > 1 {
+			g.pop()
+		}
+	})
+}
+
+// Replace swaps the top scene with the scene of the mode given.
+func (g *Game) Replace(mode common.Mode) {
+	g.transit(mode, func(s common.Scene) {
+		if len(g.stack) > 0 {
+			g.pop()
+		}
+
+		g.push(s)
+	})
+}
+
+// transit changes the scenes with the scene of the mode given while the
+// transition covers the screen, and the scene is created while it's covered.
+// The first scene is shown straight away since there's nothing to cover.
+func (g *Game) transit(mode common.Mode, change func(s common.Scene)) {
+	g.changes = append(g.changes, func() {
+		if len(g.stack) == 0 {
+			if s, ok := g.newScene(mode); ok {
+				change(s)
+			}
+
+			return
+		}
+
+		var s common.Scene
+		g.transition.Start(transition.Steps(
+			func() error {
+				s, _ = g.newScene(mode)
+				return nil
+			},
+			func() error {
+				if s != nil {
+					change(s)
+				}
+
+				return nil
+			},
+		))
+	})
+}
+
+// newScene creates the scene of the mode given. The scenes are kept the same
+// if it can't be created.
+func (g *Game) newScene(mode common.Mode) (common.Scene, bool) {
+	newScene, ok := g.scenes[mode]
+	if !ok {
+		log.Printf("no scene for mode %d", mode)
+		return nil, false
+	}
+
+	s, err := newScene()
+	if err != nil {
+		// There's nothing to go back to without a scene.
+		if len(g.stack) == 0 {
+			log.Fatalf("scene %d: %v", mode, err)
+		}
+
+		log.Printf("scene %d: %v", mode, err)
+		return nil, false
+	}
+
+	return s, true
+}
+
+func (g *Game) push(s common.Scene) {
+	g.stack = append(g.stack, s)
+	s.Enter()
+}
+
+func (g *Game) pop() {
+	top := g.stack[len(g.stack)-1]
+	g.stack = g.stack[:len(g.stack)-1]
+
+	top.Exit()
+	top.Unload()
+}
+
+// changeScenes makes the changes to the scenes in the order that they were
+// asked for. The changes after one that starts a transition wait for it to
+// finish.
+func (g *Game) changeScenes() {
+	for len(g.changes) > 0 && !g.transition.Running() {
+		change := g.changes[0]
+		g.changes = g.changes[1:]
+
+		change()
+	}
+}
+
+// below returns the index of the lowest scene that's still updated or drawn,
+// which is the top scene unless it's an overlay that lets the scenes under it
+// run.
+func (g *Game) below(runs func(o common.Overlay) bool) int {
+	i := len(g.stack) - 1
+	for i > 0 {
+		o, ok := g.stack[i].(common.Overlay)
+		if !ok || !runs(o) {
+			break
+		}
+
+		i--
+	}
+
+	return i
 }
 
 // Update updates whatever scene is currently set.
-func (g *Game) Update(dt float32) {
-	g.scenes[g.mode].Update(dt)
+// The frame time is split into fixed steps so the scene is always updated at
+// the configured tick rate, no matter how fast the game is being drawn.
+func (g *Game) Update(frameTime float32) {
+	// The scenes are changed even if there isn't a tick this frame, so
+	// there's always a scene to draw.
+	g.changeScenes()
+
+	for i := g.clock.Advance(frameTime); i > 0; i-- {
+		g.Step()
+
+		// The transition's work is drawn after every part of it, so that
+		// its progress can be seen.
+		if g.transition.Working() {
+			break
+		}
+	}
+
+	// Let the drawing know how far along the next tick the game is.
+	common.Alpha = g.clock.Alpha()
 }
 
-// Draw draws the current scene that's set.
+// Step runs a single fixed update of the current scene.
+func (g *Game) Step() {
+	// Read the controls once per tick so presses are only seen once.
+	input.Actions.Update()
+
+	g.changeScenes()
+
+	dt := g.clock.Step()
+
+	// The scenes are frozen while they're being changed.
+	if g.transition.Running() {
+		if err := g.transition.Update(dt); err != nil {
+			log.Printf("scene transition: %v", err)
+		}
+
+		return
+	}
+
+	top := len(g.stack) - 1
+
+	// Only the top scene gets input, so the scenes under it see every action
+	// as let go.
+	input.Actions.Mute(true)
+	for _, s := range g.stack[g.below(common.Overlay.UpdateBelow):top] {
+		s.Update(dt)
+	}
+	input.Actions.Mute(false)
+
+	g.stack[top].Update(dt)
+}
+
+// Player returns the player of the game.
+func (g *Game) Player() *player.Player {
+	return g.player
+}
+
+// Draw draws the top scene and the scenes under it that can be seen.
 func (g *Game) Draw() {
-	g.scenes[g.mode].Draw()
+	for _, s := range g.stack[g.below(common.Overlay.DrawBelow):] {
+		s.Draw()
+	}
+
+	g.transition.Draw()
+}
+
+// UnloadScenes removes and unloads every scene, along with the changes to
+// the scenes that haven't been made yet.
+func (g *Game) UnloadScenes() {
+	g.changes = nil
+
+	for len(g.stack) > 0 {
+		g.pop()
+	}
 }
 
-// Unload unloads all assets loaded in by raylib.
+// Unload unloads every scene and then all assets loaded in by raylib.
 func (g *Game) Unload() {
-	// Thinking about removing until we have other things that aren't raylib
-	// to unload in the scenes.
-	// for _, s := range g.scenes {
-	// 	s.Unload()
-	// }
+	g.UnloadScenes()
 
 	// Unload all raylib assets.
 	r.UnloadAll()
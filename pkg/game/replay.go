@@ -0,0 +1,31 @@
+package game
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+	"github.com/damienfamed75/rayrem/pkg/replay"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// PlayReplay plays back the replay in a new game without drawing it and
+// returns where the player ended up. The game's scenes are unloaded once
+// it's over, so replays can be played one after another.
+func PlayReplay(rp *replay.Replay) r.Vector2 {
+	common.SeedRandom(rp.Seed)
+
+	g := NewGame()
+	defer g.UnloadScenes()
+
+	playback := replay.NewPlayback(rp)
+	input.Actions.SetSource(playback)
+	input.Actions.Reset()
+
+	g.SetScene(rp.Mode)
+
+	for !playback.Done() {
+		g.Step()
+	}
+
+	return g.Player().Rigidbody.Position()
+}
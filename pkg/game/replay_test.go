@@ -0,0 +1,77 @@
+package game
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/replay"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+func TestMain(m *testing.M) {
+	// Nothing can be loaded into raylib without a window.
+	common.Headless = true
+
+	// The public config is read from the working directory, which is the
+	// root of the repository when the game is run.
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.Chdir("../.."); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := common.LoadConfig(); err != nil {
+		log.Fatalf("config: %v", err)
+	}
+
+	if err := os.Chdir(wd); err != nil {
+		log.Fatal(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// TestReplays plays back the recordings in testdata and checks that the
+// player ends up where it was recorded. Replays have to be recorded again
+// with "go run . -record" when the config or the physics change on purpose.
+func TestReplays(t *testing.T) {
+	replays := []struct {
+		file  string
+		final r.Vector2
+	}{
+		// Walks into a wall, jumps over it and walks back a little.
+		{"walk.rpl", r.NewVector2(516.7391, 184)},
+		// Pauses against the wall, moves the selection down and back up to
+		// resume, and then jumps over the wall.
+		{"pause.rpl", r.NewVector2(544, 184)},
+	}
+
+	for _, tt := range replays {
+		t.Run(tt.file, func(t *testing.T) {
+			rp, err := replay.Load(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := rp.Check(); err != nil {
+				t.Fatal(err)
+			}
+
+			if rp.Final != tt.final {
+				t.Errorf("recorded at %v, want %v", rp.Final, tt.final)
+			}
+
+			pos := PlayReplay(rp)
+			if dist := pos.Subtract(tt.final).Length(); dist > 0.01 {
+				t.Errorf("player ended at %v, want %v", pos, tt.final)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package input
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/common"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Device is the kind of input that a binding reads.
+type Device int
+
+// Devices that actions can be bound to.
+const (
+	DeviceKey Device = iota
+	DeviceButton
+	DeviceAxis
+)
+
+// Binding is a single key, gamepad button or gamepad axis that an action is
+// bound to.
+type Binding struct {
+	Device Device
+
+	Key    r.Key
+	Button r.GamepadButton
+	Axis   r.GamepadAxis
+
+	// Direction is which way an axis has to be pushed, -1 or 1.
+	Direction float32
+	// Deadzone is how far an axis has to be pushed before it counts.
+	Deadzone float32
+}
+
+// KeyBinding binds an action to a keyboard key.
+func KeyBinding(key r.Key) Binding {
+	return Binding{Device: DeviceKey, Key: key}
+}
+
+// ButtonBinding binds an action to a gamepad button.
+func ButtonBinding(button r.GamepadButton) Binding {
+	return Binding{Device: DeviceButton, Button: button}
+}
+
+// ControlBinding binds an action to a key or gamepad button from the config.
+func ControlBinding(c common.Control) Binding {
+	if c.Gamepad {
+		return ButtonBinding(c.Button)
+	}
+
+	return KeyBinding(c.Key)
+}
+
+// AxisBinding binds an action to a gamepad axis being pushed in the direction
+// given past the deadzone.
+func AxisBinding(axis r.GamepadAxis, direction, deadzone float32) Binding {
+	return Binding{
+		Device:    DeviceAxis,
+		Axis:      axis,
+		Direction: direction,
+		Deadzone:  deadzone,
+	}
+}
+
+// value returns how far the binding is pushed from 0 to 1. Keys and buttons
+// are either 0 or 1, while axes are scaled so the deadzone is 0.
+func (b Binding) value(s Source) float32 {
+	switch b.Device {
+	case DeviceKey:
+		if s.KeyDown(b.Key) {
+			return 1
+		}
+	case DeviceButton:
+		if s.ButtonDown(b.Button) {
+			return 1
+		}
+	case DeviceAxis:
+		v := s.AxisValue(b.Axis) * b.Direction
+		if v <= b.Deadzone {
+			return 0
+		}
+		if b.Deadzone >= 1 {
+			return 1
+		}
+
+		v = (v - b.Deadzone) / (1 - b.Deadzone)
+		if v > 1 {
+			v = 1
+		}
+		return v
+	}
+
+	return 0
+}
@@ -0,0 +1,221 @@
+// Package input maps named actions, like jumping, to any number of keys,
+// gamepad buttons and gamepad axes. The state of every action is read once
+// per update, so presses and releases are only ever seen on a single update
+// no matter how many updates a frame runs.
+package input
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/common"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Action is the name of something that can be done with the controls.
+type Action string
+
+// Actions that are used by the game.
+const (
+	Left     Action = "left"
+	Right    Action = "right"
+	Down     Action = "down"
+	Jump     Action = "jump"
+	Shoot    Action = "shoot"
+	Interact Action = "interact"
+
+	// Confirm and Back are used to move through menus.
+	Confirm Action = "confirm"
+	Back    Action = "back"
+	// Pause opens and closes the pause menu.
+	Pause Action = "pause"
+)
+
+// axisDeadzone is the default deadzone for the gamepad sticks.
+const axisDeadzone = 0.25
+
+// Actions is the action map that the game reads from. It's updated by the
+// game once per update.
+var Actions = NewMap(&Raylib{Gamepad: r.GamepadPlayer1})
+
+// Map keeps track of the bindings of every action and whether the actions are
+// being pressed.
+type Map struct {
+	source   Source
+	bindings map[Action][]Binding
+	// order is the order that the actions were bound in.
+	order []Action
+
+	values   map[Action]float32
+	previous map[Action]float32
+	// muted is true while every action has to read as let go.
+	muted bool
+
+	// OnUpdate is called at the end of every update if it isn't nil, after
+	// the actions have been read.
+	OnUpdate func(m *Map)
+}
+
+// NewMap returns a map without any bindings that reads from the source given.
+func NewMap(source Source) *Map {
+	return &Map{
+		source:   source,
+		bindings: make(map[Action][]Binding),
+		values:   make(map[Action]float32),
+		previous: make(map[Action]float32),
+	}
+}
+
+// Source returns where the map reads its input from.
+func (m *Map) Source() Source {
+	return m.source
+}
+
+// SetSource changes where the map reads its input from.
+func (m *Map) SetSource(source Source) {
+	m.source = source
+}
+
+// Bind adds bindings to the action.
+func (m *Map) Bind(a Action, bindings ...Binding) {
+	if _, ok := m.bindings[a]; !ok {
+		m.order = append(m.order, a)
+	}
+
+	m.bindings[a] = append(m.bindings[a], bindings...)
+}
+
+// SetBindings replaces all of the action's bindings.
+func (m *Map) SetBindings(a Action, bindings ...Binding) {
+	if _, ok := m.bindings[a]; !ok {
+		m.order = append(m.order, a)
+	}
+
+	m.bindings[a] = append([]Binding(nil), bindings...)
+}
+
+// Bindings returns the bindings of the action.
+func (m *Map) Bindings(a Action) []Binding {
+	return m.bindings[a]
+}
+
+// Actions returns every action that has been bound in the order they were
+// bound in.
+func (m *Map) Actions() []Action {
+	return m.order
+}
+
+// Update reads the state of every action from the source. It should be
+// called once at the start of every update.
+func (m *Map) Update() {
+	m.previous, m.values = m.values, m.previous
+
+	if as, ok := m.source.(ActionSource); ok {
+		// The source knows the actions itself, so the bindings aren't used.
+		for a := range m.values {
+			delete(m.values, a)
+		}
+		as.ReadActions(m.values)
+	} else {
+		for _, a := range m.order {
+			var value float32
+			for _, b := range m.bindings[a] {
+				if v := b.value(m.source); v > value {
+					value = v
+				}
+			}
+
+			m.values[a] = value
+		}
+	}
+
+	if m.OnUpdate != nil {
+		m.OnUpdate(m)
+	}
+}
+
+// Reset lets go of every action, as if nothing had been held before.
+func (m *Map) Reset() {
+	m.values = make(map[Action]float32)
+	m.previous = make(map[Action]float32)
+}
+
+// Mute makes every action read as let go while muted is true, without
+// forgetting the state of the actions. It's used for updating things that
+// shouldn't be controlled right now.
+func (m *Map) Mute(muted bool) {
+	m.muted = muted
+}
+
+// Pressed returns if the action started being held during this update.
+func (m *Map) Pressed(a Action) bool {
+	return !m.muted && m.values[a] > 0 && m.previous[a] == 0
+}
+
+// Held returns if the action is being held.
+func (m *Map) Held(a Action) bool {
+	return !m.muted && m.values[a] > 0
+}
+
+// Released returns if the action stopped being held during this update.
+func (m *Map) Released(a Action) bool {
+	return !m.muted && m.values[a] == 0 && m.previous[a] > 0
+}
+
+// Value returns how far the action is being pushed from 0 to 1. Actions
+// bound to keys and buttons are always either 0 or 1.
+func (m *Map) Value(a Action) float32 {
+	if m.muted {
+		return 0
+	}
+
+	return m.values[a]
+}
+
+// Axis returns the value of the positive action minus the negative action,
+// which is from -1 to 1.
+func (m *Map) Axis(negative, positive Action) float32 {
+	return m.Value(positive) - m.Value(negative)
+}
+
+// Load replaces the bindings of the game's actions with the keys from the
+// config along with the default gamepad bindings.
+func Load() {
+	Actions.SetBindings(Left,
+		ControlBinding(common.Controls.Left),
+		ButtonBinding(r.GamepadButtonLeftFaceLeft),
+		AxisBinding(r.GamepadAxisLeftX, -1, axisDeadzone),
+	)
+	Actions.SetBindings(Right,
+		ControlBinding(common.Controls.Right),
+		ButtonBinding(r.GamepadButtonLeftFaceRight),
+		AxisBinding(r.GamepadAxisLeftX, 1, axisDeadzone),
+	)
+	Actions.SetBindings(Down,
+		ControlBinding(common.Controls.Down),
+		ButtonBinding(r.GamepadButtonLeftFaceDown),
+		AxisBinding(r.GamepadAxisLeftY, 1, 0.5),
+	)
+	Actions.SetBindings(Jump,
+		ControlBinding(common.Controls.Jump),
+		ButtonBinding(r.GamepadButtonRightFaceDown),
+	)
+	Actions.SetBindings(Shoot,
+		ControlBinding(common.Controls.Shoot),
+		ButtonBinding(r.GamepadButtonRightFaceLeft),
+	)
+	Actions.SetBindings(Interact,
+		ControlBinding(common.Controls.Interact),
+		ButtonBinding(r.GamepadButtonRightFaceUp),
+	)
+	Actions.SetBindings(Confirm,
+		ControlBinding(common.Controls.Confirm),
+		ButtonBinding(r.GamepadButtonRightFaceDown),
+	)
+	Actions.SetBindings(Back,
+		ControlBinding(common.Controls.Back),
+		ButtonBinding(r.GamepadButtonRightFaceRight),
+	)
+	Actions.SetBindings(Pause,
+		ControlBinding(common.Controls.Pause),
+		ButtonBinding(r.GamepadButtonMiddleRight),
+	)
+}
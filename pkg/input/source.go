@@ -0,0 +1,105 @@
+package input
+
+import (
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ Source = &Raylib{}
+	_ Source = &Scripted{}
+)
+
+// Source is where the state of the keys, buttons and axes are read from.
+type Source interface {
+	KeyDown(key r.Key) bool
+	ButtonDown(button r.GamepadButton) bool
+	// AxisValue returns how far the axis is pushed from -1 to 1.
+	AxisValue(axis r.GamepadAxis) float32
+}
+
+// ActionSource is a source that knows the value of every action itself
+// instead of the keys that they're bound to, such as a replay.
+type ActionSource interface {
+	Source
+	// ReadActions sets the values of the actions for the next update.
+	ReadActions(values map[Action]float32)
+}
+
+// Raylib reads the keyboard and a gamepad through raylib.
+type Raylib struct {
+	Gamepad r.GamepadNumber
+}
+
+// KeyDown returns if the key is being held down.
+func (s *Raylib) KeyDown(key r.Key) bool {
+	return r.IsKeyDown(key)
+}
+
+// ButtonDown returns if the gamepad button is being held down. Gamepads that
+// aren't plugged in never have any buttons down.
+func (s *Raylib) ButtonDown(button r.GamepadButton) bool {
+	return r.IsGamepadAvailable(s.Gamepad) && r.IsGamepadButtonDown(s.Gamepad, button)
+}
+
+// AxisValue returns how far the gamepad axis is pushed.
+func (s *Raylib) AxisValue(axis r.GamepadAxis) float32 {
+	if !r.IsGamepadAvailable(s.Gamepad) {
+		return 0
+	}
+
+	return r.GetGamepadAxisMovement(s.Gamepad, axis)
+}
+
+// Scripted is a source that is told what is being held down instead of
+// reading a real device. It's used to drive the game without a window.
+type Scripted struct {
+	keys    map[r.Key]bool
+	buttons map[r.GamepadButton]bool
+	axes    map[r.GamepadAxis]float32
+}
+
+// NewScripted returns a source with nothing held down.
+func NewScripted() *Scripted {
+	return &Scripted{
+		keys:    make(map[r.Key]bool),
+		buttons: make(map[r.GamepadButton]bool),
+		axes:    make(map[r.GamepadAxis]float32),
+	}
+}
+
+// SetKey holds the key down or lets go of it.
+func (s *Scripted) SetKey(key r.Key, down bool) {
+	s.keys[key] = down
+}
+
+// SetButton holds the gamepad button down or lets go of it.
+func (s *Scripted) SetButton(button r.GamepadButton, down bool) {
+	s.buttons[button] = down
+}
+
+// SetAxis pushes the gamepad axis to the value given.
+func (s *Scripted) SetAxis(axis r.GamepadAxis, value float32) {
+	s.axes[axis] = value
+}
+
+// Reset lets go of everything.
+func (s *Scripted) Reset() {
+	s.keys = make(map[r.Key]bool)
+	s.buttons = make(map[r.GamepadButton]bool)
+	s.axes = make(map[r.GamepadAxis]float32)
+}
+
+// KeyDown returns if the key was set to be down.
+func (s *Scripted) KeyDown(key r.Key) bool {
+	return s.keys[key]
+}
+
+// ButtonDown returns if the gamepad button was set to be down.
+func (s *Scripted) ButtonDown(button r.GamepadButton) bool {
+	return s.buttons[button]
+}
+
+// AxisValue returns the value that the gamepad axis was set to.
+func (s *Scripted) AxisValue(axis r.GamepadAxis) float32 {
+	return s.axes[axis]
+}
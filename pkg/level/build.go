@@ -0,0 +1,144 @@
+package level
+
+import (
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/ease"
+	"github.com/damienfamed75/rayrem/pkg/factory"
+	"github.com/damienfamed75/rayrem/pkg/msg"
+	// Registers the objects with the factory.
+	_ "github.com/damienfamed75/rayrem/pkg/object"
+	"github.com/damienfamed75/rayrem/pkg/physics"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Build creates the solids, zones and objects of the level and inserts them
+// into the world. Everything that was created is returned in the order that
+// it should be drawn in, which is the tile layers followed by everything else
+// in the order that it's in the file.
+func (l *Level) Build(world physics.Broadphase) (_ []interface{}, err error) {
+	// Tiles are only drawn, so they aren't in the world. They're built first
+	// so that nothing is inserted into the world when a tileset is missing.
+	tiles, err := l.buildTiles()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			unloadTiles(tiles)
+		}
+	}()
+
+	built := make([]interface{}, 0, len(l.Solids)+len(l.Zones)+len(l.Objects))
+
+	for i := range l.Solids {
+		s, err := l.Solids[i].build()
+		if err != nil {
+			return nil, fmt.Errorf("solids[%d]: %w", i, err)
+		}
+
+		built = append(built, s)
+	}
+
+	for _, z := range l.Zones {
+		zone := physics.NewZone(z.X, z.Y, z.W, z.H, msg.Mailbox, z.Message)
+		// Zones in levels are only for the player.
+		zone.SetMask(physics.LayerPlayer)
+
+		built = append(built, zone)
+	}
+
+	objects, err := l.buildObjects()
+	if err != nil {
+		return nil, err
+	}
+	built = append(built, objects...)
+
+	if err = world.InsertI(built...); err != nil {
+		return nil, fmt.Errorf("insert: %w", err)
+	}
+
+	return append(tiles, built...), nil
+}
+
+// unloadTiles frees the textures of the tile layers, which are shared between
+// all of them.
+func unloadTiles(tiles []interface{}) {
+	for _, t := range tiles {
+		t.(*tileMap).Unload()
+	}
+}
+
+// build creates the shape of the solid.
+func (s *Solid) build() (physics.Shape, error) {
+	var shape physics.Shape
+
+	switch s.Type {
+	case SolidRectangle:
+		shape = physics.NewRectangle(s.X, s.Y, s.W, s.H)
+	case SolidPlatform:
+		shape = physics.NewPlatform(s.X, s.Y, s.W, s.H)
+	case SolidSlope:
+		shape = physics.NewSlope(s.Points[0].Vector(), s.Points[1].Vector())
+	case SolidSlopePlatform:
+		shape = physics.NewSlopePlatform(s.Points[0].Vector(), s.Points[1].Vector(), s.Depth)
+	case SolidPolygon:
+		shape = physics.NewConvexPolygon(vectors(s.Points)...)
+	case SolidCircle:
+		shape = physics.NewCircle(s.X, s.Y, s.Radius)
+	case SolidMovingPlatform:
+		p := physics.NewMovingPlatform(s.W, s.H, s.Speed, vectors(s.Points)...)
+		p.SetMode(pathModes[s.Mode])
+
+		if f, ok := ease.Named(s.Ease); ok {
+			p.SetEase(f)
+		}
+
+		shape = p
+	default:
+		return nil, fmt.Errorf("unknown solid type %q", s.Type)
+	}
+
+	if s.Material != "" {
+		m, err := physics.MaterialNamed(s.Material)
+		if err != nil {
+			return nil, err
+		}
+
+		shape.SetMaterial(m)
+	}
+
+	return shape, nil
+}
+
+// buildObjects creates the objects of the level with the factory.
+func (l *Level) buildObjects() ([]interface{}, error) {
+	objects := make([]factory.Object, len(l.Objects))
+	for i, o := range l.Objects {
+		props := make(factory.Properties, len(o.Properties)+3)
+		for k, v := range o.Properties {
+			props[k] = v
+		}
+
+		props["x"], props["y"] = o.X, o.Y
+		if o.Lock != "" {
+			props["lock"] = o.Lock
+		}
+
+		objects[i] = factory.Object{Type: o.Type, ID: o.ID, Properties: props}
+	}
+
+	return factory.Build(objects)
+}
+
+// vectors returns the points as raylib vectors.
+func vectors(points []Point) []r.Vector2 {
+	vv := make([]r.Vector2, len(points))
+	for i, p := range points {
+		vv[i] = p.Vector()
+	}
+
+	return vv
+}
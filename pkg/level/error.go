@@ -0,0 +1,127 @@
+package level
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error is a problem with a level file.
+type Error struct {
+	File string
+	// Line is the line that the problem is on, or 0 if it isn't known.
+	Line int
+	// Field is the field with the problem, such as "objects[1].lock".
+	Field string
+	Err   error
+
+	// path is the field as the keys and indexes to follow to find it.
+	path []interface{}
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	b.WriteString(e.File)
+	if e.Line > 0 {
+		b.WriteString(":" + strconv.Itoa(e.Line))
+	}
+	b.WriteString(": ")
+
+	if e.Field != "" {
+		b.WriteString(e.Field + ": ")
+	}
+	b.WriteString(e.Err.Error())
+
+	return b.String()
+}
+
+// Unwrap returns the problem without where it happened.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors are every problem that was found in a level file.
+type Errors []*Error
+
+func (ee Errors) Error() string {
+	lines := make([]string, len(ee))
+	for i, e := range ee {
+		lines[i] = e.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// fieldError returns an error for the field at the path, which is made of
+// object keys and array indexes.
+func fieldError(path []interface{}, format string, args ...interface{}) *Error {
+	// Copy the path since the caller keeps appending to its own.
+	p := append([]interface{}(nil), path...)
+
+	return &Error{
+		Field: fieldName(p),
+		Err:   fmt.Errorf(format, args...),
+		path:  p,
+	}
+}
+
+// fieldName writes a path like "objects[1].lock".
+func fieldName(path []interface{}) string {
+	var b strings.Builder
+
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(p)
+		case int:
+			b.WriteString("[" + strconv.Itoa(p) + "]")
+		}
+	}
+
+	return b.String()
+}
+
+// decodeError adds the line to errors from decoding the JSON.
+func decodeError(file string, data []byte, err error) error {
+	e := &Error{File: file, Err: err}
+
+	switch t := err.(type) {
+	case *json.SyntaxError:
+		e.Line = lineAt(data, t.Offset)
+	case *json.UnmarshalTypeError:
+		e.Line = lineAt(data, t.Offset)
+		e.Field = t.Field
+		e.Err = fmt.Errorf("expected %s but got %s", t.Type, t.Value)
+	default:
+		// Unknown fields don't say where they are, so find the first key
+		// with the same name.
+		const unknown = "json: unknown field "
+		if msg := err.Error(); strings.HasPrefix(msg, unknown) {
+			name := strings.TrimPrefix(msg, unknown)
+			e.Err = fmt.Errorf("unknown field %s", name)
+
+			if i := bytes.Index(data, []byte(name+":")); i >= 0 {
+				e.Line = lineAt(data, int64(i))
+			} else if i := bytes.Index(data, []byte(name)); i >= 0 {
+				e.Line = lineAt(data, int64(i))
+			}
+		}
+	}
+
+	return e
+}
+
+// lineAt returns the line that the byte offset is on, starting at 1.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
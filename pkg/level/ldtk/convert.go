@@ -0,0 +1,205 @@
+package ldtk
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+// converter turns the levels of a project into levels.
+type converter struct {
+	// names are the identifiers of levels by their IID.
+	names map[string]string
+	// collisions are how IntGrid values collide by their layer definition.
+	collisions map[int]map[int]level.TileCollision
+	// tilesets are the tilesets by their UID, with their first IDs set so
+	// that every tileset of the project can be used in every level, and
+	// order is their UIDs in the order that they're defined in.
+	tilesets map[int]level.Tileset
+	order    []int
+}
+
+func newConverter(file string, p *project, jls []*jsonLevel) (*converter, error) {
+	c := &converter{
+		names:      make(map[string]string),
+		collisions: make(map[int]map[int]level.TileCollision),
+		tilesets:   make(map[int]level.Tileset),
+	}
+
+	for _, jl := range jls {
+		c.names[jl.IID] = jl.Identifier
+	}
+
+	for _, def := range p.Defs.Layers {
+		values := make(map[int]level.TileCollision)
+		for _, v := range def.IntGridValues {
+			values[v.Value] = collision(v.Identifier)
+		}
+
+		c.collisions[def.UID] = values
+	}
+
+	firstID := 1
+	for _, def := range p.Defs.Tilesets {
+		// Tilesets without an image, like LDtk's icons, can't be drawn.
+		if def.RelPath == nil {
+			continue
+		}
+
+		image := path.Join(path.Dir(file), *def.RelPath)
+		if !strings.HasPrefix(image, "/assets/") {
+			return nil, fmt.Errorf("tileset %q: image %s isn't in /assets", def.Identifier, image)
+		}
+
+		c.tilesets[def.UID] = level.Tileset{
+			FirstID:    firstID,
+			Image:      strings.TrimPrefix(image, "/assets/"),
+			TileWidth:  def.GridSize,
+			TileHeight: def.GridSize,
+			Columns:    def.Columns,
+			Margin:     def.Padding,
+			Spacing:    def.Spacing,
+		}
+		c.order = append(c.order, def.UID)
+
+		firstID += def.Columns * def.Rows
+	}
+
+	return c, nil
+}
+
+// level converts a level of the project.
+func (c *converter) level(jl *jsonLevel) (*level.Level, error) {
+	l := &level.Level{
+		Version: level.Version,
+		Name:    jl.Identifier,
+		World: level.Rect{
+			X: float32(jl.WorldX),
+			Y: float32(jl.WorldY),
+			W: float32(jl.Width),
+			H: float32(jl.Height),
+		},
+	}
+
+	for _, n := range jl.Neighbours {
+		// Levels above and below in GridVania worlds aren't walked into.
+		if n.Dir == "<" || n.Dir == ">" {
+			continue
+		}
+
+		l.Neighbours = append(l.Neighbours, c.names[n.LevelIID])
+	}
+
+	// Only the tilesets that the level uses are added to it.
+	used := make(map[int]bool)
+
+	var entities []*Entity
+
+	// The first layer is drawn on top, so they're added backwards.
+	for i := len(jl.Layers) - 1; i >= 0; i-- {
+		ly := &jl.Layers[i]
+
+		switch ly.Type {
+		case layerIntGrid:
+			if err := c.intGrid(l, ly); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", ly.Identifier, err)
+			}
+		case layerEntities:
+			for j := range ly.Entities {
+				entities = append(entities, newEntity(jl.IID, &ly.Entities[j]))
+			}
+		}
+
+		if !ly.Visible || ly.TilesetUID == nil {
+			continue
+		}
+
+		if err := c.tiles(l, ly); err != nil {
+			return nil, fmt.Errorf("layer %q: %w", ly.Identifier, err)
+		}
+		used[*ly.TilesetUID] = true
+	}
+
+	for _, uid := range c.order {
+		if used[uid] {
+			l.Tilesets = append(l.Tilesets, c.tilesets[uid])
+		}
+	}
+
+	for _, e := range entities {
+		add, ok := entityFuncs[e.Identifier]
+		if !ok {
+			add = addObject
+		}
+
+		if err := add(e, l); err != nil {
+			return nil, fmt.Errorf("entity %s: %w", e.IID, err)
+		}
+	}
+
+	if err := link(l, entities); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// intGrid adds the solids of an IntGrid layer.
+func (c *converter) intGrid(l *level.Level, ly *jsonLayer) error {
+	if len(ly.IntGrid) != ly.Width*ly.Height {
+		return fmt.Errorf("layer is %dx%d but has %d cells", ly.Width, ly.Height, len(ly.IntGrid))
+	}
+
+	values := c.collisions[ly.LayerDefUID]
+
+	cells := make([]level.TileCollision, len(ly.IntGrid))
+	for i, v := range ly.IntGrid {
+		cells[i] = values[v]
+	}
+
+	l.Solids = append(l.Solids, level.TileSolids(cells, ly.Width, ly.Height, ly.GridSize, ly.GridSize)...)
+	return nil
+}
+
+// tiles adds the tiles of a layer. Tiles that are on top of other tiles in
+// the same cell are put in extra layers above it, since a tile layer only has
+// one tile per cell.
+func (c *converter) tiles(l *level.Level, ly *jsonLayer) error {
+	set, ok := c.tilesets[*ly.TilesetUID]
+	if !ok {
+		return fmt.Errorf("tileset %d has no image", *ly.TilesetUID)
+	}
+
+	var layers []level.TileLayer
+	for _, tiles := range [][]jsonTile{ly.GridTiles, ly.AutoTiles} {
+		for _, t := range tiles {
+			x, y := t.Px[0]/ly.GridSize, t.Px[1]/ly.GridSize
+			if x < 0 || y < 0 || x >= ly.Width || y >= ly.Height {
+				continue
+			}
+			cell := y*ly.Width + x
+
+			i := 0
+			for i < len(layers) && layers[i].Tiles[cell] != 0 {
+				i++
+			}
+			if i == len(layers) {
+				layers = append(layers, level.TileLayer{
+					Name:       ly.Identifier,
+					Width:      ly.Width,
+					Height:     ly.Height,
+					TileWidth:  ly.GridSize,
+					TileHeight: ly.GridSize,
+					Tiles:      make([]int, ly.Width*ly.Height),
+				})
+			}
+
+			layers[i].Tiles[cell] = set.FirstID + t.T
+		}
+	}
+
+	l.TileLayers = append(l.TileLayers, layers...)
+	return nil
+}
@@ -0,0 +1,249 @@
+package ldtk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/factory"
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+// Entity is an entity instance in an LDtk level.
+type Entity struct {
+	Identifier string
+	IID        string
+	// X and Y are the top left corner of the entity and W and H are its size.
+	X float32
+	Y float32
+	W float32
+	H float32
+
+	// Fields are the entity's fields by their identifier, which are still
+	// JSON since their type depends on the field.
+	Fields map[string]json.RawMessage
+
+	// levelIID is the level that the entity is in.
+	levelIID string
+}
+
+// EntityFunc adds an entity to a level.
+type EntityFunc func(e *Entity, l *level.Level) error
+
+// entityFuncs are the functions that add entities by their identifier.
+var entityFuncs = map[string]EntityFunc{
+	"Door":  addDoor,
+	"Key":   addKey,
+	"Spawn": addSpawn,
+	"Zone":  addZone,
+}
+
+// RegisterEntity sets the function that adds entities with the identifier
+// given, which replaces the one that was there before.
+func RegisterEntity(identifier string, add EntityFunc) {
+	entityFuncs[identifier] = add
+}
+
+func newEntity(levelIID string, je *jsonEntity) *Entity {
+	e := &Entity{
+		Identifier: je.Identifier,
+		IID:        je.IID,
+		// Entities are positioned by their pivot.
+		X:        je.Px[0] - je.Pivot[0]*je.Width,
+		Y:        je.Px[1] - je.Pivot[1]*je.Height,
+		W:        je.Width,
+		H:        je.Height,
+		Fields:   make(map[string]json.RawMessage, len(je.Fields)),
+		levelIID: levelIID,
+	}
+
+	for _, f := range je.Fields {
+		e.Fields[f.Identifier] = f.Value
+	}
+
+	return e
+}
+
+// field returns the value of a field, whose identifier doesn't have to be the
+// same case. Fields that are null aren't returned.
+func (e *Entity) field(name string) (json.RawMessage, bool) {
+	if v, ok := e.Fields[name]; ok {
+		return v, string(v) != "null"
+	}
+
+	for id, v := range e.Fields {
+		if strings.EqualFold(id, name) && string(v) != "null" {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// String returns a string field, or an empty string if it isn't set.
+func (e *Entity) String(name string) (string, error) {
+	raw, ok := e.field(name)
+	if !ok {
+		return "", nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", fmt.Errorf("field %q: %w", name, err)
+	}
+
+	return s, nil
+}
+
+// Ref returns the IID of the entity that an entity reference field refers
+// to, or an empty string if it isn't set. References to entities in other
+// levels are errors, since levels are built separately.
+func (e *Entity) Ref(name string) (string, error) {
+	raw, ok := e.field(name)
+	if !ok {
+		return "", nil
+	}
+
+	var ref struct {
+		EntityIID string `json:"entityIid"`
+		LevelIID  string `json:"levelIid"`
+	}
+	if err := json.Unmarshal(raw, &ref); err != nil {
+		return "", fmt.Errorf("field %q: %w", name, err)
+	}
+
+	if ref.LevelIID != "" && ref.LevelIID != e.levelIID {
+		return "", fmt.Errorf("field %q: %s is in another level", name, ref.EntityIID)
+	}
+
+	return ref.EntityIID, nil
+}
+
+func addDoor(e *Entity, l *level.Level) error {
+	lock, err := e.Ref("lock")
+	if err != nil {
+		return err
+	}
+
+	l.Objects = append(l.Objects, level.Object{
+		Type: level.ObjectDoor,
+		ID:   e.IID,
+		X:    e.X,
+		Y:    e.Y,
+		Lock: lock,
+	})
+
+	return nil
+}
+
+func addKey(e *Entity, l *level.Level) error {
+	l.Objects = append(l.Objects, level.Object{
+		Type: level.ObjectKey,
+		ID:   e.IID,
+		X:    e.X,
+		Y:    e.Y,
+	})
+
+	return nil
+}
+
+// addSpawn adds a spawn that's named by its "name" field, or its IID if it
+// doesn't have one.
+func addSpawn(e *Entity, l *level.Level) error {
+	name, err := e.String("name")
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = e.IID
+	}
+
+	l.Spawns = append(l.Spawns, level.Spawn{Name: name, X: e.X, Y: e.Y})
+	return nil
+}
+
+func addZone(e *Entity, l *level.Level) error {
+	message, err := e.String("message")
+	if err != nil {
+		return err
+	}
+
+	l.Zones = append(l.Zones, level.Zone{X: e.X, Y: e.Y, W: e.W, H: e.H, Message: message})
+	return nil
+}
+
+// addObject adds an entity that doesn't have a function of its own as an
+// object that's created by the factory. Its type is its identifier, or its
+// identifier in lower case, and its fields are its properties. Entity
+// references are the IDs of the entities that they refer to.
+func addObject(e *Entity, l *level.Level) error {
+	typ := e.Identifier
+	if !factory.Has(typ) {
+		typ = strings.ToLower(typ)
+	}
+	if !factory.Has(typ) {
+		return fmt.Errorf("unknown entity %q", e.Identifier)
+	}
+
+	props := map[string]interface{}{"width": e.W, "height": e.H}
+	for name, raw := range e.Fields {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+
+		if ref, ok := v.(map[string]interface{}); ok && ref["entityIid"] != nil {
+			id, err := e.Ref(name)
+			if err != nil {
+				return err
+			}
+
+			v = id
+		}
+
+		props[name] = v
+	}
+
+	l.Objects = append(l.Objects, level.Object{
+		Type:       typ,
+		ID:         e.IID,
+		X:          e.X,
+		Y:          e.Y,
+		Properties: props,
+	})
+
+	return nil
+}
+
+// link locks doors with the keys that refer to them with their "door" field,
+// which is the other way that a key and a door can be linked.
+func link(l *level.Level, entities []*Entity) error {
+	objects := make(map[string]*level.Object)
+	for i := range l.Objects {
+		objects[l.Objects[i].ID] = &l.Objects[i]
+	}
+
+	for _, e := range entities {
+		key, ok := objects[e.IID]
+		if !ok || key.Type != level.ObjectKey {
+			continue
+		}
+
+		door, err := e.Ref("door")
+		if err != nil {
+			return fmt.Errorf("entity %s: %w", e.IID, err)
+		}
+		if door == "" {
+			continue
+		}
+
+		d, ok := objects[door]
+		if !ok || d.Type != level.ObjectDoor {
+			return fmt.Errorf("entity %s: door %s isn't a door", e.IID, door)
+		}
+
+		d.Lock = key.ID
+	}
+
+	return nil
+}
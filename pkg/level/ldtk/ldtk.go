@@ -0,0 +1,186 @@
+// Package ldtk imports projects made with the LDtk level editor. Every level
+// in a project becomes a level that's linked to its neighbours by where they
+// are in the world. Importing the package registers the ".ldtk" format with
+// the level package.
+//
+// IntGrid values collide by their identifier, which is "solid" or
+// "platform", optionally followed by an underscore and the name of a physics
+// material like "solid_ice". Tiles and auto-layer tiles are drawn with their
+// tilesets, whose images have to be in /assets.
+//
+// Entities are added to levels by the function registered for their
+// identifier. Doors, keys, spawns and zones are registered by default, and
+// any other entity is an object that's created by the factory package.
+package ldtk
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+func init() {
+	level.RegisterWorldFormat(".ldtk", Parse)
+}
+
+// Layer types.
+const (
+	layerIntGrid  = "IntGrid"
+	layerEntities = "Entities"
+)
+
+type project struct {
+	Defs struct {
+		Layers []struct {
+			UID           int `json:"uid"`
+			IntGridValues []struct {
+				Value      int    `json:"value"`
+				Identifier string `json:"identifier"`
+			} `json:"intGridValues"`
+		} `json:"layers"`
+		Tilesets []struct {
+			UID        int     `json:"uid"`
+			Identifier string  `json:"identifier"`
+			RelPath    *string `json:"relPath"`
+			GridSize   int     `json:"tileGridSize"`
+			Spacing    int     `json:"spacing"`
+			Padding    int     `json:"padding"`
+			Columns    int     `json:"__cWid"`
+			Rows       int     `json:"__cHei"`
+		} `json:"tilesets"`
+	} `json:"defs"`
+
+	Levels []*jsonLevel `json:"levels"`
+	// Worlds are only used by projects with multiple worlds, which have
+	// their levels in their worlds instead.
+	Worlds []struct {
+		Levels []*jsonLevel `json:"levels"`
+	} `json:"worlds"`
+}
+
+type jsonLevel struct {
+	Identifier string `json:"identifier"`
+	IID        string `json:"iid"`
+	WorldX     int    `json:"worldX"`
+	WorldY     int    `json:"worldY"`
+	Width      int    `json:"pxWid"`
+	Height     int    `json:"pxHei"`
+	// ExternalRelPath is the file that the level is in if the project saves
+	// levels separately.
+	ExternalRelPath *string `json:"externalRelPath"`
+
+	Layers     []jsonLayer `json:"layerInstances"`
+	Neighbours []struct {
+		LevelIID string `json:"levelIid"`
+		Dir      string `json:"dir"`
+	} `json:"__neighbours"`
+}
+
+type jsonLayer struct {
+	Identifier  string       `json:"__identifier"`
+	Type        string       `json:"__type"`
+	Width       int          `json:"__cWid"`
+	Height      int          `json:"__cHei"`
+	GridSize    int          `json:"__gridSize"`
+	TilesetUID  *int         `json:"__tilesetDefUid"`
+	LayerDefUID int          `json:"layerDefUid"`
+	IntGrid     []int        `json:"intGridCsv"`
+	GridTiles   []jsonTile   `json:"gridTiles"`
+	AutoTiles   []jsonTile   `json:"autoLayerTiles"`
+	Entities    []jsonEntity `json:"entityInstances"`
+	Visible     bool         `json:"visible"`
+}
+
+type jsonTile struct {
+	Px [2]int `json:"px"`
+	T  int    `json:"t"`
+}
+
+type jsonEntity struct {
+	Identifier string     `json:"__identifier"`
+	IID        string     `json:"iid"`
+	Px         [2]float32 `json:"px"`
+	Pivot      [2]float32 `json:"__pivot"`
+	Width      float32    `json:"width"`
+	Height     float32    `json:"height"`
+	Fields     []struct {
+		Identifier string          `json:"__identifier"`
+		Value      json.RawMessage `json:"__value"`
+	} `json:"fieldInstances"`
+}
+
+// Parse reads an LDtk project and returns its levels. Levels that are saved
+// in separate files are read relative to the project with pkger.
+func Parse(file string, data []byte) ([]*level.Level, error) {
+	var p project
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	jls := p.Levels
+	for _, w := range p.Worlds {
+		jls = append(jls, w.Levels...)
+	}
+
+	for i, jl := range jls {
+		if jl.ExternalRelPath == nil {
+			continue
+		}
+
+		external := path.Join(path.Dir(file), *jl.ExternalRelPath)
+
+		raw, err := level.ReadFile(external)
+		if err != nil {
+			return nil, fmt.Errorf("%s: level %q: %w", file, jl.Identifier, err)
+		}
+
+		if err := json.Unmarshal(raw, &jls[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", external, err)
+		}
+	}
+
+	c, err := newConverter(file, &p, jls)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	levels := make([]*level.Level, len(jls))
+	for i, jl := range jls {
+		l, err := c.level(jl)
+		if err != nil {
+			return nil, fmt.Errorf("%s: level %q: %w", file, jl.Identifier, err)
+		}
+
+		if err := l.Check(file + ":" + jl.Identifier); err != nil {
+			return nil, err
+		}
+
+		levels[i] = l
+	}
+
+	return levels, nil
+}
+
+// collision returns how an IntGrid value with the identifier collides.
+func collision(identifier string) level.TileCollision {
+	parts := strings.SplitN(identifier, "_", 2)
+
+	var c level.TileCollision
+	switch strings.ToLower(parts[0]) {
+	case "solid":
+		c.Solid = true
+	case "platform":
+		c.Platform = true
+	default:
+		return c
+	}
+
+	if len(parts) == 2 {
+		c.Material = parts[1]
+	}
+
+	return c
+}
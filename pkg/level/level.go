@@ -0,0 +1,301 @@
+// Package level loads levels from JSON files. A level file describes the
+// solid geometry of a level, the objects in it and where the player can
+// spawn, and is turned into shapes and objects by Build. Other formats, like
+// the maps of level editors, can be read by registering them with
+// RegisterFormat.
+package level
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/markbates/pkger"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Version is the newest version of the level file format. Version 2 added
+// zones and tiles, and version 3 added worlds.
+const Version = 3
+
+// Level is a level file.
+type Level struct {
+	// Version is the version of the format that the file was written in.
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+
+	Spawns  []Spawn  `json:"spawns"`
+	Solids  []Solid  `json:"solids"`
+	Zones   []Zone   `json:"zones"`
+	Objects []Object `json:"objects"`
+
+	// Tilesets and TileLayers are only drawn, the solids are what the tiles
+	// collide with.
+	Tilesets   []Tileset   `json:"tilesets"`
+	TileLayers []TileLayer `json:"tileLayers"`
+
+	// World is where the level is in the world that it's a part of and how
+	// big it is. When the player leaves the level they go to whichever of the
+	// Neighbours they walked into.
+	World      Rect     `json:"world"`
+	Neighbours []string `json:"neighbours"`
+}
+
+// Rect is an area of a world.
+type Rect struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	W float32 `json:"w"`
+	H float32 `json:"h"`
+}
+
+// Contains returns if the point is in the area.
+func (rc Rect) Contains(p r.Vector2) bool {
+	return p.X >= rc.X && p.X < rc.X+rc.W && p.Y >= rc.Y && p.Y < rc.Y+rc.H
+}
+
+// Point is a position written as [x, y].
+type Point [2]float32
+
+// Vector returns the point as a raylib vector.
+func (p Point) Vector() r.Vector2 {
+	return r.NewVector2(p[0], p[1])
+}
+
+// Spawn is a place where the player can start in the level.
+type Spawn struct {
+	Name string  `json:"name"`
+	X    float32 `json:"x"`
+	Y    float32 `json:"y"`
+}
+
+// Solid types.
+const (
+	SolidRectangle      = "rectangle"
+	SolidPlatform       = "platform"
+	SolidSlope          = "slope"
+	SolidSlopePlatform  = "slopePlatform"
+	SolidPolygon        = "polygon"
+	SolidCircle         = "circle"
+	SolidMovingPlatform = "movingPlatform"
+)
+
+// Solid is a piece of the level's geometry. Which fields are used depends on
+// the type of solid.
+type Solid struct {
+	Type string `json:"type"`
+
+	// X, Y, W and H are the bounds of rectangles and platforms. Moving
+	// platforms only use the size, and circles use X and Y as their center.
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	W float32 `json:"w"`
+	H float32 `json:"h"`
+
+	Radius float32 `json:"radius"`
+	// Points are the ends of slopes, the corners of polygons and the
+	// waypoints of moving platforms.
+	Points []Point `json:"points"`
+	// Depth is how far below a slope platform the player can land on it.
+	Depth float32 `json:"depth"`
+
+	// Speed, Mode and Ease are how moving platforms move. Mode is either
+	// "loop" or "pingPong" and Ease is the name of an easing function.
+	Speed float32 `json:"speed"`
+	Mode  string  `json:"mode"`
+	Ease  string  `json:"ease"`
+
+	// Material is the name of the physics material that the solid is made of.
+	Material string `json:"material"`
+}
+
+// Zone is an area that sends a message through msg.Mailbox when the player
+// goes in or out of it.
+type Zone struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	W float32 `json:"w"`
+	H float32 `json:"h"`
+
+	// Message is the type of the message, which is sent with the phase of
+	// the overlap like physics.Enter.Of(message).
+	Message string `json:"message"`
+}
+
+// Object types that levels know about. Any type that's registered with the
+// factory package can be used.
+const (
+	ObjectKey  = "key"
+	ObjectDoor = "door"
+)
+
+// Object is a game object in the level, which is created by the factory
+// package with its properties.
+type Object struct {
+	Type string `json:"type"`
+	// ID is used by other objects to refer to this one.
+	ID string  `json:"id"`
+	X  float32 `json:"x"`
+	Y  float32 `json:"y"`
+
+	// Lock is the ID of the key that unlocks a door, which is the same as
+	// the "lock" property.
+	Lock string `json:"lock"`
+
+	// Properties are the rest of the settings of the object.
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Spawn returns where the spawn with the name given is. An empty name is the
+// first spawn in the level.
+func (l *Level) Spawn(name string) (r.Vector2, bool) {
+	for _, s := range l.Spawns {
+		if name == "" || s.Name == name {
+			return r.NewVector2(s.X, s.Y), true
+		}
+	}
+
+	return r.Vector2{}, false
+}
+
+// Format reads a level file of some format. The file name is only used in
+// errors and to find other files that the level refers to.
+type Format func(file string, data []byte) (*Level, error)
+
+// WorldFormat reads a file with every level of a world in it, where the
+// first level is where the world starts.
+type WorldFormat func(file string, data []byte) ([]*Level, error)
+
+// formats are the level formats by their file extension, which are tried in
+// the order that they were registered in.
+var formats = []struct {
+	ext   string
+	parse WorldFormat
+}{
+	{".json", single(Parse)},
+}
+
+// RegisterFormat lets Load read levels with the file extension given, such as
+// ".tmx". Packages with formats register them when they're imported.
+func RegisterFormat(ext string, parse Format) {
+	RegisterWorldFormat(ext, single(parse))
+}
+
+// RegisterWorldFormat lets Load and LoadWorld read worlds with the file
+// extension given.
+func RegisterWorldFormat(ext string, parse WorldFormat) {
+	formats = append(formats, struct {
+		ext   string
+		parse WorldFormat
+	}{ext, parse})
+}
+
+// single returns a format with one level as a world format.
+func single(parse Format) WorldFormat {
+	return func(file string, data []byte) ([]*Level, error) {
+		l, err := parse(file, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*Level{l}, nil
+	}
+}
+
+// Load loads the level with the name given from the /levels directory. The
+// first file with the name and the extension of a registered format is used.
+// If the file is a world then the level that it starts in is returned.
+func Load(name string) (*Level, error) {
+	levels, err := LoadWorld(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return levels[0], nil
+}
+
+// LoadWorld loads every level in the file with the name given from the
+// /levels directory. Files with a single level are a world of one level.
+func LoadWorld(name string) ([]*Level, error) {
+	for _, f := range formats {
+		file := path.Join("/levels", name+f.ext)
+		if _, err := pkger.Stat(file); err != nil {
+			continue
+		}
+
+		raw, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		levels, err := f.parse(file, raw)
+		if err != nil {
+			return nil, err
+		}
+		if len(levels) == 0 {
+			return nil, fmt.Errorf("%s: no levels", file)
+		}
+
+		return levels, nil
+	}
+
+	return nil, fmt.Errorf("open level: no level named %q", name)
+}
+
+// ReadFile reads a file that's packed with pkger.
+func ReadFile(file string) ([]byte, error) {
+	f, err := pkger.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("open level: %w", err)
+	}
+	defer f.Close()
+
+	raw, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading level: %w", err)
+	}
+
+	return raw, nil
+}
+
+// Parse reads and validates a level file. The file name is only used in
+// errors, which say what line and field the problem is at.
+func Parse(file string, data []byte) (*Level, error) {
+	var l Level
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&l); err != nil {
+		return nil, decodeError(file, data, err)
+	}
+
+	if errs := l.validate(); len(errs) > 0 {
+		for _, e := range errs {
+			e.File = file
+			e.Line = lineOfPath(data, e.path)
+		}
+
+		return nil, errs
+	}
+
+	return &l, nil
+}
+
+// Check returns every problem with a level that was made by something other
+// than Parse, such as an importer. The problems don't have lines.
+func (l *Level) Check(file string) error {
+	errs := l.validate()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, e := range errs {
+		e.File = file
+	}
+
+	return errs
+}
@@ -0,0 +1,173 @@
+package level
+
+// lineOfPath returns the line of the value at the path in a JSON document,
+// where the path is made of object keys and array indexes. If the value isn't
+// there then the line of the closest value that is gets returned. The data
+// has to already be valid JSON.
+func lineOfPath(data []byte, path []interface{}) int {
+	s := &jsonScanner{data: data}
+	s.skipSpace()
+
+	for _, p := range path {
+		var found bool
+		switch p := p.(type) {
+		case string:
+			found = s.enterKey(p)
+		case int:
+			found = s.enterIndex(p)
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return lineAt(data, int64(s.pos))
+}
+
+// jsonScanner walks through JSON without decoding it.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *jsonScanner) peek() byte {
+	if s.pos >= len(s.data) {
+		return 0
+	}
+
+	return s.data[s.pos]
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// enterKey moves to the value of the key in the object that the scanner is
+// at. The scanner doesn't move if the key isn't there.
+func (s *jsonScanner) enterKey(key string) bool {
+	if s.peek() != '{' {
+		return false
+	}
+
+	start := s.pos
+	s.pos++
+
+	for {
+		s.skipSpace()
+		if s.peek() != '"' {
+			break
+		}
+
+		k := s.readString()
+		s.skipSpace()
+		s.pos++ // :
+		s.skipSpace()
+
+		if k == key {
+			return true
+		}
+
+		s.skipValue()
+		s.skipSpace()
+		if s.peek() != ',' {
+			break
+		}
+		s.pos++
+	}
+
+	s.pos = start
+	return false
+}
+
+// enterIndex moves to the element at the index of the array that the scanner
+// is at. The scanner doesn't move if the array is too short.
+func (s *jsonScanner) enterIndex(index int) bool {
+	if s.peek() != '[' {
+		return false
+	}
+
+	start := s.pos
+	s.pos++
+
+	for i := 0; ; i++ {
+		s.skipSpace()
+		if s.peek() == ']' {
+			break
+		}
+
+		if i == index {
+			return true
+		}
+
+		s.skipValue()
+		s.skipSpace()
+		if s.peek() != ',' {
+			break
+		}
+		s.pos++
+	}
+
+	s.pos = start
+	return false
+}
+
+// readString reads the string that the scanner is at without unescaping it.
+func (s *jsonScanner) readString() string {
+	s.pos++ // opening quote
+
+	start := s.pos
+	for s.pos < len(s.data) && s.data[s.pos] != '"' {
+		if s.data[s.pos] == '\\' {
+			s.pos++
+		}
+		s.pos++
+	}
+
+	str := string(s.data[start:s.pos])
+	s.pos++ // closing quote
+
+	return str
+}
+
+// skipValue moves past the value that the scanner is at.
+func (s *jsonScanner) skipValue() {
+	switch s.peek() {
+	case '"':
+		s.readString()
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case '"':
+				s.readString()
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+
+			s.pos++
+			if depth == 0 {
+				return
+			}
+		}
+	default:
+		// Numbers, true, false and null.
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return
+			}
+			s.pos++
+		}
+	}
+}
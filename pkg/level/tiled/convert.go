@@ -0,0 +1,263 @@
+package tiled
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/damienfamed75/rayrem/pkg/factory"
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+// converter adds the layers of a map to a level.
+type converter struct {
+	*tiledMap
+	level *level.Level
+
+	// doors are the indexes of the doors in the level's objects with the
+	// lock property that they had, which is resolved once every key is known.
+	doors map[int]string
+	// keys are the IDs of keys by their name.
+	keys map[string]string
+}
+
+// tiles adds a tile layer and the solids of its tiles.
+func (c *converter) tiles(ly layer) error {
+	if len(ly.Data) != ly.Width*ly.Height {
+		return fmt.Errorf("layer is %dx%d but has %d tiles", ly.Width, ly.Height, len(ly.Data))
+	}
+
+	tiles := make([]int, len(ly.Data))
+	cells := make([]level.TileCollision, len(ly.Data))
+
+	for i, gid := range ly.Data {
+		if gid == 0 {
+			continue
+		}
+
+		// Flipped tiles are drawn the way that they are in the tileset.
+		tiles[i] = int(gid &^ flipped)
+
+		props := ly.Properties
+		if _, t := c.tile(gid); t != nil {
+			props = props.with(t.Properties)
+		}
+
+		cells[i] = level.TileCollision{
+			Solid:    props.bool(TypeSolid),
+			Platform: props.bool(TypePlatform),
+			Material: props["material"],
+		}
+	}
+
+	c.level.TileLayers = append(c.level.TileLayers, level.TileLayer{
+		Name:       ly.Name,
+		Width:      ly.Width,
+		Height:     ly.Height,
+		TileWidth:  c.TileWidth,
+		TileHeight: c.TileHeight,
+		Tiles:      tiles,
+	})
+
+	c.level.Solids = append(c.level.Solids, level.TileSolids(cells, ly.Width, ly.Height, c.TileWidth, c.TileHeight)...)
+
+	return nil
+}
+
+// objects adds the objects of an object layer.
+func (c *converter) objects(ly layer) error {
+	for _, o := range ly.Objects {
+		props := ly.Properties
+
+		// Tile objects get the type and properties of their tile, and are
+		// positioned by their bottom left corner.
+		if o.GID != 0 {
+			if _, t := c.tile(o.GID); t != nil {
+				props = props.with(t.Properties)
+				if o.Type == "" {
+					o.Type = t.Type
+				}
+			}
+
+			o.Y -= o.Height
+		}
+		o.Properties = props.with(o.Properties)
+
+		if err := c.object(o); err != nil {
+			return fmt.Errorf("object %d: %w", o.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// object adds an object by its type.
+func (c *converter) object(o object) error {
+	if o.Rotation != 0 {
+		return fmt.Errorf("rotated objects aren't supported")
+	}
+
+	id := strconv.Itoa(o.ID)
+
+	switch o.Type {
+	case "", TypeSolid, TypePlatform:
+		if o.Point && o.Type == "" {
+			return c.spawn(o)
+		}
+
+		return c.solid(o)
+	case TypeZone:
+		c.level.Zones = append(c.level.Zones, level.Zone{
+			X:       o.X,
+			Y:       o.Y,
+			W:       o.Width,
+			H:       o.Height,
+			Message: o.Properties["message"],
+		})
+	case TypeSpawn:
+		return c.spawn(o)
+	case level.ObjectKey:
+		if c.keys == nil {
+			c.keys = make(map[string]string)
+		}
+		if o.Name != "" {
+			c.keys[o.Name] = id
+		}
+
+		c.level.Objects = append(c.level.Objects, level.Object{
+			Type: level.ObjectKey,
+			ID:   id,
+			X:    o.X,
+			Y:    o.Y,
+		})
+	case level.ObjectDoor:
+		if c.doors == nil {
+			c.doors = make(map[int]string)
+		}
+		if lock := o.Properties["lock"]; lock != "" {
+			c.doors[len(c.level.Objects)] = lock
+		}
+
+		c.level.Objects = append(c.level.Objects, level.Object{
+			Type: level.ObjectDoor,
+			ID:   id,
+			X:    o.X,
+			Y:    o.Y,
+		})
+	default:
+		if !factory.Has(o.Type) {
+			return fmt.Errorf("unknown object type %q", o.Type)
+		}
+
+		// Any other object is made by the factory with its properties.
+		props := make(map[string]interface{}, len(o.Properties)+2)
+		for k, v := range o.Properties {
+			props[k] = v
+		}
+		props["width"], props["height"] = o.Width, o.Height
+
+		c.level.Objects = append(c.level.Objects, level.Object{
+			Type:       o.Type,
+			ID:         id,
+			X:          o.X,
+			Y:          o.Y,
+			Properties: props,
+		})
+	}
+
+	return nil
+}
+
+// spawn adds a spawn, which is named after the object's ID if it has no
+// name.
+func (c *converter) spawn(o object) error {
+	name := o.Name
+	if name == "" {
+		name = strconv.Itoa(o.ID)
+	}
+
+	c.level.Spawns = append(c.level.Spawns, level.Spawn{Name: name, X: o.X, Y: o.Y})
+	return nil
+}
+
+// solid adds the shape of a solid or platform object.
+func (c *converter) solid(o object) error {
+	material := o.Properties["material"]
+
+	switch {
+	case len(o.Polyline) > 0:
+		depth, ok, err := o.Properties.float("depth")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			depth = float32(c.TileHeight)
+		}
+
+		for i := 1; i < len(o.Polyline); i++ {
+			a, b := o.Polyline[i-1], o.Polyline[i]
+
+			c.level.Solids = append(c.level.Solids, level.Solid{
+				Type: level.SolidSlopePlatform,
+				Points: []level.Point{
+					{o.X + a[0], o.Y + a[1]},
+					{o.X + b[0], o.Y + b[1]},
+				},
+				Depth:    depth,
+				Material: material,
+			})
+		}
+	case len(o.Polygon) > 0:
+		points := make([]level.Point, len(o.Polygon))
+		for i, p := range o.Polygon {
+			points[i] = level.Point{o.X + p[0], o.Y + p[1]}
+		}
+
+		c.level.Solids = append(c.level.Solids, level.Solid{
+			Type:     level.SolidPolygon,
+			Points:   points,
+			Material: material,
+		})
+	case o.Ellipse:
+		if o.Width != o.Height {
+			return fmt.Errorf("only circles are supported, not ellipses")
+		}
+
+		c.level.Solids = append(c.level.Solids, level.Solid{
+			Type:     level.SolidCircle,
+			X:        o.X + o.Width/2,
+			Y:        o.Y + o.Height/2,
+			Radius:   o.Width / 2,
+			Material: material,
+		})
+	case o.Point:
+		return fmt.Errorf("points can't be solid")
+	default:
+		s := level.Solid{
+			Type:     level.SolidRectangle,
+			X:        o.X,
+			Y:        o.Y,
+			W:        o.Width,
+			H:        o.Height,
+			Material: material,
+		}
+		if o.Type == TypePlatform {
+			s.Type = level.SolidPlatform
+		}
+
+		c.level.Solids = append(c.level.Solids, s)
+	}
+
+	return nil
+}
+
+// locks sets the locks of doors. A lock is either the ID of a key object,
+// which is what object properties are, or the name of a key.
+func (c *converter) locks() {
+	for i, lock := range c.doors {
+		if id, ok := c.keys[lock]; ok {
+			lock = id
+		}
+
+		c.level.Objects[i].Lock = lock
+	}
+}
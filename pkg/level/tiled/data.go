@@ -0,0 +1,87 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// decodeData decodes the tiles of a tile layer that are encoded as text. The
+// encoding is either "csv" or "base64", and base64 tiles can be compressed
+// with "zlib" or "gzip".
+func decodeData(text, encoding, compression string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		if compression != "" {
+			return nil, fmt.Errorf("csv tiles can't be compressed")
+		}
+
+		return decodeCSV(text)
+	case "base64":
+		return decodeBase64(text, compression)
+	default:
+		return nil, fmt.Errorf("unknown tile encoding %q", encoding)
+	}
+}
+
+// decodeCSV decodes tiles that are separated by commas.
+func decodeCSV(text string) ([]uint32, error) {
+	fields := strings.Split(strings.TrimSpace(text), ",")
+
+	gids := make([]uint32, len(fields))
+	for i, f := range fields {
+		gid, err := strconv.ParseUint(strings.TrimSpace(f), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("tile %d: %w", i, err)
+		}
+
+		gids[i] = uint32(gid)
+	}
+
+	return gids, nil
+}
+
+// decodeBase64 decodes tiles that are little endian 32 bit numbers.
+func decodeBase64(text, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, fmt.Errorf("decoding tiles: %w", err)
+	}
+
+	var r io.Reader = bytes.NewReader(raw)
+	switch compression {
+	case "":
+	case "zlib":
+		r, err = zlib.NewReader(r)
+	case "gzip":
+		r, err = gzip.NewReader(r)
+	default:
+		return nil, fmt.Errorf("unknown tile compression %q", compression)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decompressing tiles: %w", err)
+	}
+
+	raw, err = ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing tiles: %w", err)
+	}
+
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("tiles are %d bytes, which isn't a multiple of 4", len(raw))
+	}
+
+	gids := make([]uint32, len(raw)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+
+	return gids, nil
+}
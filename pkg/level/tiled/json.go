@@ -0,0 +1,254 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+// ParseJSON reads a map in Tiled's JSON format. Tilesets in other files are
+// read relative to the map with pkger.
+func ParseJSON(file string, data []byte) (*level.Level, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	m, err := jm.tiledMap()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if err := m.loadTilesets(file, decodeJSONTileset); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return m.convert(file)
+}
+
+type jsonMap struct {
+	Width      int            `json:"width"`
+	Height     int            `json:"height"`
+	TileWidth  int            `json:"tilewidth"`
+	TileHeight int            `json:"tileheight"`
+	Infinite   bool           `json:"infinite"`
+	Tilesets   []jsonTileset  `json:"tilesets"`
+	Layers     []jsonLayer    `json:"layers"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonTileset struct {
+	FirstGID   int    `json:"firstgid"`
+	Source     string `json:"source"`
+	Name       string `json:"name"`
+	Image      string `json:"image"`
+	TileWidth  int    `json:"tilewidth"`
+	TileHeight int    `json:"tileheight"`
+	Columns    int    `json:"columns"`
+	Margin     int    `json:"margin"`
+	Spacing    int    `json:"spacing"`
+	Tiles      []struct {
+		ID         int            `json:"id"`
+		Type       string         `json:"type"`
+		Class      string         `json:"class"`
+		Properties jsonProperties `json:"properties"`
+	} `json:"tiles"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Data        json.RawMessage `json:"data"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+	Properties  jsonProperties  `json:"properties"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float32        `json:"x"`
+	Y          float32        `json:"y"`
+	Width      float32        `json:"width"`
+	Height     float32        `json:"height"`
+	Rotation   float32        `json:"rotation"`
+	GID        uint32         `json:"gid"`
+	Point      bool           `json:"point"`
+	Ellipse    bool           `json:"ellipse"`
+	Polygon    []jsonPoint    `json:"polygon"`
+	Polyline   []jsonPoint    `json:"polyline"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonPoint struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// jsonProperties are custom properties, which are a list of names and values.
+type jsonProperties []struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+func (jp jsonProperties) properties() properties {
+	p := make(properties, len(jp))
+	for _, prop := range jp {
+		p[prop.Name] = fmt.Sprint(prop.Value)
+	}
+
+	return p
+}
+
+func (jm *jsonMap) tiledMap() (*tiledMap, error) {
+	m := &tiledMap{
+		Width:      jm.Width,
+		Height:     jm.Height,
+		TileWidth:  jm.TileWidth,
+		TileHeight: jm.TileHeight,
+		Infinite:   jm.Infinite,
+		Properties: jm.Properties.properties(),
+	}
+
+	for _, jt := range jm.Tilesets {
+		m.Tilesets = append(m.Tilesets, jt.tileset())
+	}
+
+	layers, err := jsonLayers(jm.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+
+	return m, nil
+}
+
+func (jt *jsonTileset) tileset() tileset {
+	t := tileset{
+		FirstGID:   jt.FirstGID,
+		Source:     jt.Source,
+		Name:       jt.Name,
+		Image:      jt.Image,
+		TileWidth:  jt.TileWidth,
+		TileHeight: jt.TileHeight,
+		Columns:    jt.Columns,
+		Margin:     jt.Margin,
+		Spacing:    jt.Spacing,
+	}
+
+	for _, tile := range jt.Tiles {
+		t.Tiles = append(t.Tiles, newTile(tile.ID, tile.Type, tile.Class, tile.Properties.properties()))
+	}
+
+	return t
+}
+
+// decodeJSONTileset reads a tileset from a .tsj file.
+func decodeJSONTileset(file string, data []byte) (tileset, error) {
+	var jt jsonTileset
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return tileset{}, err
+	}
+
+	return jt.tileset(), nil
+}
+
+func jsonLayers(jls []jsonLayer) ([]layer, error) {
+	layers := make([]layer, 0, len(jls))
+	for _, jl := range jls {
+		ly := layer{
+			Type:       jl.Type,
+			Name:       jl.Name,
+			Width:      jl.Width,
+			Height:     jl.Height,
+			Properties: jl.Properties.properties(),
+		}
+
+		if len(jl.Data) > 0 {
+			var err error
+			if ly.Data, err = jl.data(); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", jl.Name, err)
+			}
+		}
+
+		for _, jo := range jl.Objects {
+			ly.Objects = append(ly.Objects, jo.object())
+		}
+
+		children, err := jsonLayers(jl.Layers)
+		if err != nil {
+			return nil, err
+		}
+		ly.Layers = children
+
+		layers = append(layers, ly)
+	}
+
+	return layers, nil
+}
+
+// data decodes the tiles of the layer, which are either a list of numbers or
+// a base64 string.
+func (jl *jsonLayer) data() ([]uint32, error) {
+	if jl.Encoding == "" || jl.Encoding == "csv" {
+		var gids []uint32
+		if err := json.Unmarshal(jl.Data, &gids); err != nil {
+			return nil, fmt.Errorf("decoding tiles: %w", err)
+		}
+
+		return gids, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(jl.Data, &text); err != nil {
+		return nil, fmt.Errorf("decoding tiles: %w", err)
+	}
+
+	return decodeData(text, jl.Encoding, jl.Compression)
+}
+
+func (jo *jsonObject) object() object {
+	o := object{
+		ID:         jo.ID,
+		Name:       jo.Name,
+		Type:       jo.Type,
+		X:          jo.X,
+		Y:          jo.Y,
+		Width:      jo.Width,
+		Height:     jo.Height,
+		Rotation:   jo.Rotation,
+		GID:        jo.GID,
+		Point:      jo.Point,
+		Ellipse:    jo.Ellipse,
+		Properties: jo.Properties.properties(),
+	}
+	if o.Type == "" {
+		o.Type = jo.Class
+	}
+
+	for _, p := range jo.Polygon {
+		o.Polygon = append(o.Polygon, level.Point{p.X, p.Y})
+	}
+	for _, p := range jo.Polyline {
+		o.Polyline = append(o.Polyline, level.Point{p.X, p.Y})
+	}
+
+	return o
+}
+
+// newTile returns a tile with its type, which newer versions of Tiled call
+// its class.
+func newTile(id int, typ, class string, props properties) tile {
+	if typ == "" {
+		typ = class
+	}
+
+	return tile{ID: id, Type: typ, Properties: props}
+}
@@ -0,0 +1,314 @@
+// Package tiled imports maps made with the Tiled map editor, in either its
+// JSON (.tmj) or XML (.tmx) format. Importing the package registers both
+// formats with the level package.
+//
+// Tile layers are drawn with their tilesets. Tiles that have the "solid"
+// property, or that are in a layer with it, collide and are merged into as
+// few rectangles as possible. Tiles with the "platform" property are one way
+// platforms. Both use the "material" property as their physics material.
+//
+// Objects are converted by their type (or class):
+//
+//	"" or "solid"  rectangles, polygons and circles that collide
+//	"platform"     one way platforms, or slope platforms if it's a polyline
+//	"zone"         a zone that sends its "message" property
+//	"spawn"        a spawn, which every point without a type also is
+//	"key"          a key
+//	"door"         a door, locked by the key in its "lock" property
+//
+// Objects of any other type that's registered with the factory package are
+// created by it with their properties.
+//
+// Polylines become a slope platform for every segment, which the player can
+// land on from as far below as the "depth" property.
+package tiled
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+func init() {
+	level.RegisterFormat(".tmj", ParseJSON)
+	level.RegisterFormat(".tmx", ParseTMX)
+}
+
+// Object types.
+const (
+	TypeSolid    = "solid"
+	TypePlatform = "platform"
+	TypeZone     = "zone"
+	TypeSpawn    = "spawn"
+)
+
+// Bits of a global tile ID that say how the tile is flipped.
+const (
+	flippedHorizontally = 0x80000000
+	flippedVertically   = 0x40000000
+	flippedDiagonally   = 0x20000000
+	rotatedHexagonal    = 0x10000000
+
+	flipped = flippedHorizontally | flippedVertically | flippedDiagonally | rotatedHexagonal
+)
+
+// tiledMap is a map in either format.
+type tiledMap struct {
+	Width      int
+	Height     int
+	TileWidth  int
+	TileHeight int
+	Infinite   bool
+
+	Tilesets   []tileset
+	Layers     []layer
+	Properties properties
+}
+
+// tileset is a tileset in either format. Tilesets in other files only have
+// FirstGID and Source until they're loaded.
+type tileset struct {
+	FirstGID int
+	Source   string
+
+	Name       string
+	Image      string
+	TileWidth  int
+	TileHeight int
+	Columns    int
+	Margin     int
+	Spacing    int
+
+	Tiles []tile
+}
+
+// tile is the type and properties of a tile in a tileset.
+type tile struct {
+	ID         int
+	Type       string
+	Properties properties
+}
+
+// Layer types.
+const (
+	layerTiles   = "tilelayer"
+	layerObjects = "objectgroup"
+	layerGroup   = "group"
+)
+
+// layer is a layer in either format.
+type layer struct {
+	Type string
+	Name string
+
+	Width  int
+	Height int
+	// Data are the global tile IDs of a tile layer.
+	Data []uint32
+
+	Objects []object
+	// Layers are the layers in a group.
+	Layers []layer
+
+	Properties properties
+}
+
+// object is an object in either format.
+type object struct {
+	ID   int
+	Name string
+	Type string
+
+	X      float32
+	Y      float32
+	Width  float32
+	Height float32
+
+	Rotation float32
+	// GID is the global tile ID of the tile if the object is a tile.
+	GID uint32
+
+	Point    bool
+	Ellipse  bool
+	Polygon  []level.Point
+	Polyline []level.Point
+
+	Properties properties
+}
+
+// properties are custom properties by name. Every value is kept as text.
+type properties map[string]string
+
+// bool returns if the property is true.
+func (p properties) bool(name string) bool {
+	b, _ := strconv.ParseBool(p[name])
+	return b
+}
+
+// float returns the property as a number.
+func (p properties) float(name string) (float32, bool, error) {
+	v, ok := p[name]
+	if !ok {
+		return 0, false, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("property %q: %w", name, err)
+	}
+
+	return float32(f), true, nil
+}
+
+// with returns the properties with the ones given on top.
+func (p properties) with(over properties) properties {
+	merged := make(properties, len(p)+len(over))
+	for k, v := range p {
+		merged[k] = v
+	}
+	for k, v := range over {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// loadTilesets loads tilesets that are in other files, with decode for
+// reading them. Every tileset's image is made relative to /assets.
+func (m *tiledMap) loadTilesets(file string, decode func(file string, data []byte) (tileset, error)) error {
+	for i, t := range m.Tilesets {
+		dir := path.Dir(file)
+
+		if t.Source != "" {
+			source := path.Join(dir, t.Source)
+
+			raw, err := level.ReadFile(source)
+			if err != nil {
+				return fmt.Errorf("tileset %s: %w", t.Source, err)
+			}
+
+			loaded, err := decode(source, raw)
+			if err != nil {
+				return fmt.Errorf("tileset %s: %w", t.Source, err)
+			}
+
+			loaded.FirstGID = t.FirstGID
+			t = loaded
+			dir = path.Dir(source)
+		}
+
+		if t.Image == "" {
+			return fmt.Errorf("tileset %q: image collection tilesets aren't supported", t.Name)
+		}
+
+		// Images are loaded as assets, so they have to be in /assets.
+		image := path.Join(dir, t.Image)
+		if !strings.HasPrefix(image, "/assets/") {
+			return fmt.Errorf("tileset %q: image %s isn't in /assets", t.Name, image)
+		}
+		t.Image = strings.TrimPrefix(image, "/assets/")
+
+		m.Tilesets[i] = t
+	}
+
+	return nil
+}
+
+// tile returns the tileset and tile of the global tile ID. The tile is nil if
+// the tileset doesn't have a type or properties for it.
+func (m *tiledMap) tile(gid uint32) (*tileset, *tile) {
+	id := int(gid &^ flipped)
+
+	var set *tileset
+	for i := range m.Tilesets {
+		t := &m.Tilesets[i]
+		if t.FirstGID <= id && (set == nil || t.FirstGID > set.FirstGID) {
+			set = t
+		}
+	}
+	if set == nil {
+		return nil, nil
+	}
+
+	for i := range set.Tiles {
+		if set.Tiles[i].ID == id-set.FirstGID {
+			return set, &set.Tiles[i]
+		}
+	}
+
+	return set, nil
+}
+
+// convert turns the map into a level and checks it.
+func (m *tiledMap) convert(file string) (*level.Level, error) {
+	if m.Infinite {
+		return nil, fmt.Errorf("%s: infinite maps aren't supported", file)
+	}
+
+	name := m.Properties["name"]
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	}
+
+	l := &level.Level{
+		Version: level.Version,
+		Name:    name,
+	}
+
+	for _, t := range m.Tilesets {
+		l.Tilesets = append(l.Tilesets, level.Tileset{
+			FirstID:    t.FirstGID,
+			Image:      t.Image,
+			TileWidth:  t.TileWidth,
+			TileHeight: t.TileHeight,
+			Columns:    t.Columns,
+			Margin:     t.Margin,
+			Spacing:    t.Spacing,
+		})
+	}
+
+	c := converter{tiledMap: m, level: l}
+	for _, ly := range flatten(m.Layers, nil) {
+		var err error
+		switch ly.Type {
+		case layerTiles:
+			err = c.tiles(ly)
+		case layerObjects:
+			err = c.objects(ly)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: layer %q: %w", file, ly.Name, err)
+		}
+	}
+
+	c.locks()
+
+	if err := l.Check(file); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// flatten returns the layers with every group replaced by the layers in it,
+// in the order that they're drawn in. Layers get the properties of their
+// groups unless they have their own.
+func flatten(layers []layer, inherited properties) []layer {
+	var flat []layer
+	for _, ly := range layers {
+		ly.Properties = inherited.with(ly.Properties)
+
+		if ly.Type == layerGroup {
+			flat = append(flat, flatten(ly.Layers, ly.Properties)...)
+			continue
+		}
+
+		flat = append(flat, ly)
+	}
+
+	return flat
+}
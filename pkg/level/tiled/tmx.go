@@ -0,0 +1,315 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/level"
+)
+
+// ParseTMX reads a map in Tiled's XML format. Tilesets in other files are
+// read relative to the map with pkger.
+func ParseTMX(file string, data []byte) (*level.Level, error) {
+	var xm xmlMap
+	if err := xml.Unmarshal(data, &xm); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	m, err := xm.tiledMap()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if err := m.loadTilesets(file, decodeXMLTileset); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return m.convert(file)
+}
+
+type xmlMap struct {
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Infinite   bool          `xml:"infinite,attr"`
+	Tilesets   []xmlTileset  `xml:"tileset"`
+	Properties xmlProperties `xml:"properties"`
+	// Layers are every other element, so that the order of the different
+	// kinds of layers is kept.
+	Layers []xmlLayer `xml:",any"`
+}
+
+type xmlTileset struct {
+	FirstGID   int    `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Columns    int    `xml:"columns,attr"`
+	Margin     int    `xml:"margin,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Image      struct {
+		Source string `xml:"source,attr"`
+	} `xml:"image"`
+	Tiles []struct {
+		ID         int           `xml:"id,attr"`
+		Type       string        `xml:"type,attr"`
+		Class      string        `xml:"class,attr"`
+		Properties xmlProperties `xml:"properties"`
+	} `xml:"tile"`
+}
+
+type xmlLayer struct {
+	XMLName xml.Name
+	Name    string `xml:"name,attr"`
+	Width   int    `xml:"width,attr"`
+	Height  int    `xml:"height,attr"`
+	Data    struct {
+		Encoding    string `xml:"encoding,attr"`
+		Compression string `xml:"compression,attr"`
+		Text        string `xml:",chardata"`
+		Tiles       []struct {
+			GID uint32 `xml:"gid,attr"`
+		} `xml:"tile"`
+		Chunks []struct{} `xml:"chunk"`
+	} `xml:"data"`
+	Objects    []xmlObject   `xml:"object"`
+	Properties xmlProperties `xml:"properties"`
+	// Layers are the layers in a group.
+	Layers []xmlLayer `xml:",any"`
+}
+
+type xmlObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float32       `xml:"x,attr"`
+	Y          float32       `xml:"y,attr"`
+	Width      float32       `xml:"width,attr"`
+	Height     float32       `xml:"height,attr"`
+	Rotation   float32       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Point      *struct{}     `xml:"point"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Polygon    *xmlPoints    `xml:"polygon"`
+	Polyline   *xmlPoints    `xml:"polyline"`
+	Properties xmlProperties `xml:"properties"`
+}
+
+type xmlPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+// xmlProperties are custom properties. Multiple line strings are the text of
+// the property instead of its value.
+type xmlProperties struct {
+	Properties []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+		Text  string `xml:",chardata"`
+	} `xml:"property"`
+}
+
+func (xp xmlProperties) properties() properties {
+	p := make(properties, len(xp.Properties))
+	for _, prop := range xp.Properties {
+		if prop.Value == "" {
+			prop.Value = prop.Text
+		}
+
+		p[prop.Name] = prop.Value
+	}
+
+	return p
+}
+
+func (xm *xmlMap) tiledMap() (*tiledMap, error) {
+	m := &tiledMap{
+		Width:      xm.Width,
+		Height:     xm.Height,
+		TileWidth:  xm.TileWidth,
+		TileHeight: xm.TileHeight,
+		Infinite:   xm.Infinite,
+		Properties: xm.Properties.properties(),
+	}
+
+	for _, xt := range xm.Tilesets {
+		m.Tilesets = append(m.Tilesets, xt.tileset())
+	}
+
+	layers, err := xmlLayers(xm.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.Layers = layers
+
+	return m, nil
+}
+
+func (xt *xmlTileset) tileset() tileset {
+	t := tileset{
+		FirstGID:   xt.FirstGID,
+		Source:     xt.Source,
+		Name:       xt.Name,
+		Image:      xt.Image.Source,
+		TileWidth:  xt.TileWidth,
+		TileHeight: xt.TileHeight,
+		Columns:    xt.Columns,
+		Margin:     xt.Margin,
+		Spacing:    xt.Spacing,
+	}
+
+	for _, tile := range xt.Tiles {
+		t.Tiles = append(t.Tiles, newTile(tile.ID, tile.Type, tile.Class, tile.Properties.properties()))
+	}
+
+	return t
+}
+
+// decodeXMLTileset reads a tileset from a .tsx file.
+func decodeXMLTileset(file string, data []byte) (tileset, error) {
+	var xt xmlTileset
+	if err := xml.Unmarshal(data, &xt); err != nil {
+		return tileset{}, err
+	}
+
+	return xt.tileset(), nil
+}
+
+// xmlLayerTypes are the types of layers by their element, which are the
+// same as the types in the JSON format.
+var xmlLayerTypes = map[string]string{
+	"layer":       layerTiles,
+	"objectgroup": layerObjects,
+	"group":       layerGroup,
+}
+
+func xmlLayers(xls []xmlLayer) ([]layer, error) {
+	layers := make([]layer, 0, len(xls))
+	for _, xl := range xls {
+		typ, ok := xmlLayerTypes[xl.XMLName.Local]
+		if !ok {
+			// Image layers and anything else that can't be imported.
+			continue
+		}
+
+		ly := layer{
+			Type:       typ,
+			Name:       xl.Name,
+			Width:      xl.Width,
+			Height:     xl.Height,
+			Properties: xl.Properties.properties(),
+		}
+
+		if typ == layerTiles {
+			var err error
+			if ly.Data, err = xl.data(); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", xl.Name, err)
+			}
+		}
+
+		for _, xo := range xl.Objects {
+			o, err := xo.object()
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: object %d: %w", xl.Name, xo.ID, err)
+			}
+
+			ly.Objects = append(ly.Objects, o)
+		}
+
+		if typ == layerGroup {
+			children, err := xmlLayers(xl.Layers)
+			if err != nil {
+				return nil, err
+			}
+			ly.Layers = children
+		}
+
+		layers = append(layers, ly)
+	}
+
+	return layers, nil
+}
+
+// data decodes the tiles of the layer, which are either text or a <tile>
+// element for every tile.
+func (xl *xmlLayer) data() ([]uint32, error) {
+	d := xl.Data
+	if len(d.Chunks) > 0 {
+		return nil, fmt.Errorf("infinite maps aren't supported")
+	}
+
+	if d.Encoding == "" {
+		gids := make([]uint32, len(d.Tiles))
+		for i, t := range d.Tiles {
+			gids[i] = t.GID
+		}
+
+		return gids, nil
+	}
+
+	return decodeData(d.Text, d.Encoding, d.Compression)
+}
+
+func (xo *xmlObject) object() (object, error) {
+	o := object{
+		ID:         xo.ID,
+		Name:       xo.Name,
+		Type:       xo.Type,
+		X:          xo.X,
+		Y:          xo.Y,
+		Width:      xo.Width,
+		Height:     xo.Height,
+		Rotation:   xo.Rotation,
+		GID:        xo.GID,
+		Point:      xo.Point != nil,
+		Ellipse:    xo.Ellipse != nil,
+		Properties: xo.Properties.properties(),
+	}
+	if o.Type == "" {
+		o.Type = xo.Class
+	}
+
+	var err error
+	if xo.Polygon != nil {
+		if o.Polygon, err = parsePoints(xo.Polygon.Points); err != nil {
+			return object{}, fmt.Errorf("polygon: %w", err)
+		}
+	}
+	if xo.Polyline != nil {
+		if o.Polyline, err = parsePoints(xo.Polyline.Points); err != nil {
+			return object{}, fmt.Errorf("polyline: %w", err)
+		}
+	}
+
+	return o, nil
+}
+
+// parsePoints parses points that are written like "0,0 16,-8".
+func parsePoints(s string) ([]level.Point, error) {
+	var points []level.Point
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+
+		var p level.Point
+		for i, v := range xy {
+			f, err := strconv.ParseFloat(v, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid point %q: %w", pair, err)
+			}
+
+			p[i] = float32(f)
+		}
+
+		points = append(points, p)
+	}
+
+	return points, nil
+}
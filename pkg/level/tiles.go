@@ -0,0 +1,308 @@
+package level
+
+import (
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Tileset is an image that's cut up into tiles.
+type Tileset struct {
+	// FirstID is the ID of the first tile in the tileset. Tiles in tile
+	// layers refer to the tileset with the largest FirstID that isn't more
+	// than their own ID.
+	FirstID int `json:"firstId"`
+	// Image is the asset that the tiles are in.
+	Image string `json:"image"`
+
+	TileWidth  int `json:"tileWidth"`
+	TileHeight int `json:"tileHeight"`
+	Columns    int `json:"columns"`
+	// Margin is the space around the edge of the image and Spacing is the
+	// space between tiles, both in pixels.
+	Margin  int `json:"margin"`
+	Spacing int `json:"spacing"`
+}
+
+// TileLayer is a grid of tiles that's drawn.
+type TileLayer struct {
+	Name string `json:"name"`
+	// Width and Height are the size of the layer in tiles.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// TileWidth and TileHeight are the size of the grid's cells.
+	TileWidth  int `json:"tileWidth"`
+	TileHeight int `json:"tileHeight"`
+
+	// Tiles are the IDs of each tile from left to right and top to bottom,
+	// where 0 is an empty cell.
+	Tiles []int `json:"tiles"`
+}
+
+// TileRect is a rectangle of tiles that are all the same kind.
+type TileRect struct {
+	X, Y, W, H int
+	Kind       int
+}
+
+// MergeTiles merges tiles of the same kind into as few rectangles as it can
+// so that big areas of tiles don't have to be thousands of shapes. Kinds are
+// from left to right and top to bottom, where 0 is an empty cell. Each
+// rectangle is made as wide as possible and then as tall as possible.
+func MergeTiles(kinds []int, width, height int) []TileRect {
+	var (
+		rects []TileRect
+		used  = make([]bool, len(kinds))
+	)
+
+	free := func(x, y, kind int) bool {
+		i := y*width + x
+		return !used[i] && kinds[i] == kind
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			kind := kinds[y*width+x]
+			if kind == 0 || used[y*width+x] {
+				continue
+			}
+
+			w := 1
+			for x+w < width && free(x+w, y, kind) {
+				w++
+			}
+
+			h := 1
+		grow:
+			for y+h < height {
+				for i := x; i < x+w; i++ {
+					if !free(i, y+h, kind) {
+						break grow
+					}
+				}
+				h++
+			}
+
+			for j := y; j < y+h; j++ {
+				for i := x; i < x+w; i++ {
+					used[j*width+i] = true
+				}
+			}
+
+			rects = append(rects, TileRect{X: x, Y: y, W: w, H: h, Kind: kind})
+		}
+	}
+
+	return rects
+}
+
+// TileCollision is how a tile collides. Tiles that are neither solid nor a
+// platform don't collide.
+type TileCollision struct {
+	Solid    bool
+	Platform bool
+	Material string
+}
+
+// TileSolids returns the solids of a grid of tiles. Tiles that collide the
+// same way are merged with MergeTiles, except that platforms are only merged
+// along rows since they can only be stood on from the top.
+func TileSolids(cells []TileCollision, width, height, tileWidth, tileHeight int) []Solid {
+	// Kinds are numbered from 1 so that 0 can be tiles that don't collide.
+	var kinds []TileCollision
+	solids := make([]int, len(cells))
+	platforms := make([]int, len(cells))
+
+	for i, c := range cells {
+		if !c.Solid && !c.Platform {
+			continue
+		}
+		c.Solid = !c.Platform
+
+		k := 0
+		for k < len(kinds) && kinds[k] != c {
+			k++
+		}
+		if k == len(kinds) {
+			kinds = append(kinds, c)
+		}
+
+		if c.Platform {
+			platforms[i] = k + 1
+		} else {
+			solids[i] = k + 1
+		}
+	}
+
+	rects := MergeTiles(solids, width, height)
+	for y := 0; y < height; y++ {
+		row := platforms[y*width : (y+1)*width]
+		for _, rect := range MergeTiles(row, width, 1) {
+			rect.Y = y
+			rects = append(rects, rect)
+		}
+	}
+
+	built := make([]Solid, len(rects))
+	for i, rect := range rects {
+		kind := kinds[rect.Kind-1]
+
+		built[i] = Solid{
+			Type:     SolidRectangle,
+			X:        float32(rect.X * tileWidth),
+			Y:        float32(rect.Y * tileHeight),
+			W:        float32(rect.W * tileWidth),
+			H:        float32(rect.H * tileHeight),
+			Material: kind.Material,
+		}
+		if kind.Platform {
+			built[i].Type = SolidPlatform
+		}
+	}
+
+	return built
+}
+
+// validateTiles returns the problems with the tilesets and tile layers.
+func (l *Level) validateTiles() Errors {
+	var errs Errors
+
+	for i, t := range l.Tilesets {
+		p := at("tilesets", i)
+
+		if t.FirstID <= 0 {
+			errs = append(errs, fieldError(append(p, "firstId"), "first id must be positive, got %d", t.FirstID))
+		}
+		if t.Image == "" {
+			errs = append(errs, fieldError(append(p, "image"), "missing image"))
+		}
+		if t.TileWidth <= 0 || t.TileHeight <= 0 {
+			errs = append(errs, fieldError(append(p, "tileWidth"), "tile size must be positive, got %dx%d", t.TileWidth, t.TileHeight))
+		}
+		if t.Columns <= 0 {
+			errs = append(errs, fieldError(append(p, "columns"), "columns must be positive, got %d", t.Columns))
+		}
+	}
+
+	for i, t := range l.TileLayers {
+		p := at("tileLayers", i)
+
+		if t.TileWidth <= 0 || t.TileHeight <= 0 {
+			errs = append(errs, fieldError(append(p, "tileWidth"), "tile size must be positive, got %dx%d", t.TileWidth, t.TileHeight))
+		}
+		if len(t.Tiles) != t.Width*t.Height {
+			errs = append(errs, fieldError(append(p, "tiles"), "layer is %dx%d but has %d tiles", t.Width, t.Height, len(t.Tiles)))
+		}
+
+		for j, id := range t.Tiles {
+			if id == 0 {
+				continue
+			}
+
+			if _, ok := l.tileset(id); !ok {
+				errs = append(errs, fieldError(append(p, "tiles", j), "no tileset has the tile %d", id))
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// tileset returns the tileset that the tile is in.
+func (l *Level) tileset(id int) (*Tileset, bool) {
+	var best *Tileset
+	for i := range l.Tilesets {
+		t := &l.Tilesets[i]
+		if t.FirstID <= id && (best == nil || t.FirstID > best.FirstID) {
+			best = t
+		}
+	}
+
+	return best, best != nil
+}
+
+// tileMap draws a tile layer.
+type tileMap struct {
+	layer    TileLayer
+	tiles    []tileSprite
+	textures map[string]r.Texture2D
+}
+
+// tileSprite is where a tile is in its tileset's texture.
+type tileSprite struct {
+	texture r.Texture2D
+	src     r.Rectangle
+	dest    r.Vector2
+}
+
+// buildTiles loads the textures of the tilesets and works out where every
+// tile of every layer gets drawn. Textures are shared between layers.
+func (l *Level) buildTiles() ([]interface{}, error) {
+	textures := make(map[string]r.Texture2D)
+
+	maps := make([]interface{}, 0, len(l.TileLayers))
+	for i, layer := range l.TileLayers {
+		m := &tileMap{layer: layer, textures: textures}
+
+		for j, id := range layer.Tiles {
+			if id == 0 {
+				continue
+			}
+
+			set, _ := l.tileset(id)
+
+			tex, ok := textures[set.Image]
+			if !ok {
+				img, err := common.LoadPNG(set.Image)
+				if err != nil {
+					m.Unload()
+					return nil, fmt.Errorf("tileLayers[%d]: %w", i, err)
+				}
+
+				tex = common.LoadTexture(img)
+				textures[set.Image] = tex
+			}
+
+			index := id - set.FirstID
+			col, row := index%set.Columns, index/set.Columns
+
+			m.tiles = append(m.tiles, tileSprite{
+				texture: tex,
+				src: r.NewRectangle(
+					float32(set.Margin+col*(set.TileWidth+set.Spacing)),
+					float32(set.Margin+row*(set.TileHeight+set.Spacing)),
+					float32(set.TileWidth), float32(set.TileHeight),
+				),
+				// Tiles that are taller than the grid stick out of the top
+				// of their cell, like in Tiled.
+				dest: r.NewVector2(
+					float32((j%layer.Width)*layer.TileWidth),
+					float32((j/layer.Width+1)*layer.TileHeight-set.TileHeight),
+				),
+			})
+		}
+
+		maps = append(maps, m)
+	}
+
+	return maps, nil
+}
+
+// Draw draws every tile in the layer.
+func (m *tileMap) Draw() {
+	for _, t := range m.tiles {
+		r.DrawTextureRec(t.texture, t.src, t.dest, r.White)
+	}
+}
+
+// Unload frees the textures of the tilesets. They're shared with the other
+// layers of the level, so they're only freed by the first layer unloaded.
+func (m *tileMap) Unload() {
+	for image, tex := range m.textures {
+		common.UnloadTexture(tex)
+		delete(m.textures, image)
+	}
+}
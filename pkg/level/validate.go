@@ -0,0 +1,197 @@
+package level
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/ease"
+	"github.com/damienfamed75/rayrem/pkg/factory"
+	"github.com/damienfamed75/rayrem/pkg/physics"
+)
+
+// validate returns every problem with the level that would stop it from
+// being built.
+func (l *Level) validate() Errors {
+	var errs Errors
+
+	switch {
+	case l.Version == 0:
+		errs = append(errs, fieldError(at("version"), "missing version"))
+	case l.Version > Version:
+		errs = append(errs, fieldError(at("version"),
+			"version %d is newer than the newest supported version %d", l.Version, Version))
+	case l.Version < 0:
+		errs = append(errs, fieldError(at("version"), "invalid version %d", l.Version))
+	}
+
+	// Levels in worlds can be walked into from their neighbours instead.
+	if len(l.Spawns) == 0 && len(l.Neighbours) == 0 {
+		errs = append(errs, fieldError(at("spawns"), "level needs at least one spawn"))
+	}
+
+	spawns := make(map[string]bool)
+	for i, s := range l.Spawns {
+		p := at("spawns", i, "name")
+
+		switch {
+		case s.Name == "":
+			errs = append(errs, fieldError(p, "missing name"))
+		case spawns[s.Name]:
+			errs = append(errs, fieldError(p, "duplicate spawn %q", s.Name))
+		}
+
+		spawns[s.Name] = true
+	}
+
+	for i, s := range l.Solids {
+		errs = append(errs, s.validate(at("solids", i))...)
+	}
+
+	for i, z := range l.Zones {
+		p := at("zones", i)
+
+		if z.W <= 0 || z.H <= 0 {
+			errs = append(errs, fieldError(append(p, "w"), "size must be positive, got %vx%v", z.W, z.H))
+		}
+		if z.Message == "" {
+			errs = append(errs, fieldError(append(p, "message"), "missing message"))
+		}
+	}
+
+	errs = append(errs, l.validateTiles()...)
+
+	if l.World.W < 0 || l.World.H < 0 {
+		errs = append(errs, fieldError(at("world", "w"), "size can't be negative, got %vx%v", l.World.W, l.World.H))
+	}
+	for i, n := range l.Neighbours {
+		if n == "" || n == l.Name {
+			errs = append(errs, fieldError(at("neighbours", i), "invalid neighbour %q", n))
+		}
+	}
+
+	// Find every key first so doors can refer to keys after them.
+	ids := make(map[string]string)
+	for i, o := range l.Objects {
+		if o.ID == "" {
+			continue
+		}
+
+		if _, ok := ids[o.ID]; ok {
+			errs = append(errs, fieldError(at("objects", i, "id"), "duplicate id %q", o.ID))
+			continue
+		}
+
+		ids[o.ID] = o.Type
+	}
+
+	for i, o := range l.Objects {
+		p := at("objects", i)
+
+		switch {
+		case o.Type == "":
+			errs = append(errs, fieldError(append(p, "type"), "missing type"))
+		case !factory.Has(o.Type):
+			errs = append(errs, fieldError(append(p, "type"), "unknown object type %q", o.Type))
+		case o.Type == ObjectDoor && o.Lock != "":
+			if t, ok := ids[o.Lock]; !ok {
+				errs = append(errs, fieldError(append(p, "lock"), "no object with the id %q", o.Lock))
+			} else if t != ObjectKey {
+				errs = append(errs, fieldError(append(p, "lock"), "%q is a %s, not a key", o.Lock, t))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validate returns the problems with a solid.
+func (s *Solid) validate(p []interface{}) Errors {
+	var errs Errors
+	add := func(field string, format string, args ...interface{}) {
+		errs = append(errs, fieldError(append(p, field), format, args...))
+	}
+
+	switch s.Type {
+	case SolidRectangle, SolidPlatform:
+		if s.W <= 0 || s.H <= 0 {
+			add("w", "size must be positive, got %vx%v", s.W, s.H)
+		}
+	case SolidCircle:
+		if s.Radius <= 0 {
+			add("radius", "radius must be positive, got %v", s.Radius)
+		}
+	case SolidSlope, SolidSlopePlatform:
+		if len(s.Points) != 2 {
+			add("points", "slopes need 2 points, got %d", len(s.Points))
+		} else if s.Points[0] == s.Points[1] {
+			add("points", "slope points are the same")
+		}
+
+		if s.Type == SolidSlopePlatform && s.Depth <= 0 {
+			add("depth", "depth must be positive, got %v", s.Depth)
+		}
+	case SolidPolygon:
+		if len(s.Points) < 3 {
+			add("points", "polygons need at least 3 points, got %d", len(s.Points))
+		} else if !convex(s.Points) {
+			add("points", "polygon isn't convex")
+		}
+	case SolidMovingPlatform:
+		if s.W <= 0 || s.H <= 0 {
+			add("w", "size must be positive, got %vx%v", s.W, s.H)
+		}
+		if len(s.Points) == 0 {
+			add("points", "moving platforms need at least 1 waypoint")
+		}
+		if len(s.Points) > 1 && s.Speed <= 0 {
+			add("speed", "speed must be positive, got %v", s.Speed)
+		}
+		if _, ok := pathModes[s.Mode]; !ok {
+			add("mode", "unknown mode %q", s.Mode)
+		}
+		if _, ok := ease.Named(s.Ease); s.Ease != "" && !ok {
+			add("ease", "unknown easing function %q", s.Ease)
+		}
+	case "":
+		add("type", "missing type")
+	default:
+		add("type", "unknown solid type %q", s.Type)
+	}
+
+	if s.Material != "" {
+		if _, err := physics.MaterialNamed(s.Material); err != nil {
+			add("material", "%v", err)
+		}
+	}
+
+	return errs
+}
+
+// pathModes are the moving platform modes by name.
+var pathModes = map[string]physics.PathMode{
+	"":         physics.PathLoop,
+	"loop":     physics.PathLoop,
+	"pingPong": physics.PathPingPong,
+}
+
+// convex returns if the corners of the polygon all turn the same way.
+func convex(points []Point) bool {
+	var sign float32
+	for i := range points {
+		a, b, c := points[i], points[(i+1)%len(points)], points[(i+2)%len(points)]
+
+		cross := (b[0]-a[0])*(c[1]-b[1]) - (b[1]-a[1])*(c[0]-b[0])
+		if cross == 0 {
+			continue
+		}
+
+		if sign != 0 && (cross > 0) != (sign > 0) {
+			return false
+		}
+		sign = cross
+	}
+
+	return true
+}
+
+// at returns the path made of the keys and indexes given.
+func at(pp ...interface{}) []interface{} {
+	return pp
+}
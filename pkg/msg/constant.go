@@ -6,4 +6,7 @@ const (
 	Open = "open"
 	Lock = "lock"
 	Key  = "key"
+
+	// Contact is sent through a body's mailbox when it touches a solid.
+	Contact = "contact"
 )
@@ -59,12 +59,15 @@ func (m *MessageManager) Listen(msgType string, handler MessageHandler) MessageH
 }
 
 // ListenOnce only accepts one message before being removed from the listeners.
-func (m *MessageManager) ListenOnce(msgType string, handler MessageHandler) {
+// The ID is returned so the handler can be stopped before it gets a message.
+func (m *MessageManager) ListenOnce(msgType string, handler MessageHandler) MessageHandlerID {
 	var handlerID MessageHandlerID
 	handlerID = m.Listen(msgType, func(msg Message) {
 		handler(msg)
 		m.StopListen(msgType, handlerID)
 	})
+
+	return handlerID
 }
 
 // StopListen marks the current handler to be removed from the listeners.
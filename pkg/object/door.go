@@ -2,6 +2,7 @@ package object
 
 import (
 	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
 	"github.com/damienfamed75/rayrem/pkg/msg"
 	"github.com/damienfamed75/rayrem/pkg/physics"
 
@@ -28,6 +29,19 @@ type Door struct {
 	openDir       common.Direction
 	aoaMultiplier float32 // Area of Activation Multiplier
 
+	// playerNear is true while the player is inside of the door's zone and
+	// playerOverlap is where the player last overlapped the zone.
+	playerNear    bool
+	playerOverlap r.Rectangle
+	world         physics.Broadphase
+	// listeners are the handlers that keep track of the player by the type
+	// of message that they listen to.
+	listeners map[string]msg.MessageHandlerID
+
+	// lockID is the ID of the key that locks the door when it was created by
+	// the factory, which is resolved once every object has been created.
+	lockID string
+
 	// This door can be interacted with by the player.
 	*interactable
 }
@@ -44,8 +58,8 @@ func NewDoor(pos r.Vector2, oo ...Option) *Door {
 	open, _ := common.LoadPNG("door-open.png")
 	closed, _ := common.LoadPNG("door-closed.png")
 
-	d.spriteOpen = r.LoadTextureFromGo(open)
-	d.spriteClosed = r.LoadTextureFromGo(closed)
+	d.spriteOpen = common.LoadTexture(open)
+	d.spriteClosed = common.LoadTexture(closed)
 
 	d.interactable = newInteractable(
 		msg.Door,
@@ -63,6 +77,9 @@ func NewDoor(pos r.Vector2, oo ...Option) *Door {
 		),
 	)
 
+	// Only the player can open doors.
+	d.zone.SetMask(physics.LayerPlayer)
+
 	// Apply given options.
 	for _, o := range oo {
 		o(d)
@@ -73,34 +90,63 @@ func NewDoor(pos r.Vector2, oo ...Option) *Door {
 
 // Add fills the spatial adder interface to be able to custom add itself to
 // the world.
-func (d *Door) Add(w *physics.SpatialHashmap) {
+func (d *Door) Add(w physics.Broadphase) {
 	// Insert the parts of the door into the world.
 	d.interactable.Add(w)
-
-	// Setup a mailbox to listen for door messages.
-	d.interactable.mailbox.Listen(d.interactable.msgType, func(m msg.Message) {
-		// If the lock isn't locked and and the interact key is pressed.
-		if !d.Lock.locked && r.IsKeyPressed(common.Controls.Interact) {
-			zm := m.(*physics.ZoneMessage)
-			// If the colliding zone isn't the player, then ignore this message.
-			if !zm.Entity.HasTags(common.TagPlayer) {
-				return
-			}
-
-			d.openDir = common.Right
-			// if the overlapping rectangle's max X position is less than
-			// the center of the aoa zone then open the door left.
-			if zm.Overlap.MaxPosition().X < d.zone.Rectangle.Rectangle.Center().X {
-				d.openDir = common.Left
-			}
-
-			// Remove the door from the spatial hashmap.
-			w.Remove(d.zone)
-			w.Remove(d.collider)
-			// Set open to true.
-			d.open = true
+	d.world = w
+
+	// Keep track of when the player walks in and out of the door's zone.
+	near := func(m msg.Message) {
+		zm := m.(*physics.ZoneMessage)
+		// If the colliding zone isn't the player, then ignore this message.
+		if !zm.Entity.HasTags(common.TagPlayer) {
+			return
 		}
-	})
+
+		d.playerNear = zm.Phase != physics.Exit
+		d.playerOverlap = zm.Overlap
+	}
+
+	d.listeners = make(map[string]msg.MessageHandlerID)
+	for _, phase := range []physics.Phase{physics.Enter, physics.Stay, physics.Exit} {
+		msgType := phase.Of(d.interactable.msgType)
+		d.listeners[msgType] = d.interactable.mailbox.Listen(msgType, near)
+	}
+}
+
+// Unload stops the door's handlers and frees its sprites.
+func (d *Door) Unload() {
+	for msgType, id := range d.listeners {
+		d.interactable.mailbox.StopListen(msgType, id)
+	}
+	d.listeners = nil
+
+	d.Lock.stopListen()
+
+	common.UnloadTexture(d.spriteOpen)
+	common.UnloadTexture(d.spriteClosed)
+}
+
+// Update opens the door when the player presses the interact key while
+// standing next to it.
+func (d *Door) Update(dt float32) {
+	if d.open || d.Lock.locked || !d.playerNear || !input.Actions.Pressed(input.Interact) {
+		return
+	}
+
+	d.openDir = common.Right
+	// if the overlapping rectangle's max X position is less than
+	// the center of the aoa zone then open the door left.
+	if d.playerOverlap.MaxPosition().X < d.zone.Rectangle.Rectangle.Center().X {
+		d.openDir = common.Left
+	}
+
+	// Remove the door from the spatial hashmap.
+	d.world.Remove(d.zone)
+	d.world.Remove(d.collider)
+	// Set open to true.
+	d.open = true
+	d.playerNear = false
 }
 
 // Open returns a boolean of if the door is open or closed.
@@ -0,0 +1,73 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/factory"
+)
+
+// Object types.
+const (
+	TypeKey  = "key"
+	TypeDoor = "door"
+)
+
+func init() {
+	factory.Register(TypeKey, newKeyFrom)
+	factory.Register(TypeDoor, newDoorFrom)
+}
+
+var (
+	// Doors are locked by keys after every object has been created.
+	_ factory.Resolver = &Door{}
+)
+
+// newKeyFrom creates a key at the "x" and "y" properties.
+func newKeyFrom(props factory.Properties) (interface{}, error) {
+	pos, err := props.Position()
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := NewKey(pos)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// newDoorFrom creates a door at the "x" and "y" properties that's locked by
+// the key with the ID in the "lock" property.
+func newDoorFrom(props factory.Properties) (interface{}, error) {
+	pos, err := props.Position()
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewDoor(pos)
+	d.lockID = props.String("lock")
+
+	return d, nil
+}
+
+// Resolve locks the door with the key that it was created with.
+func (d *Door) Resolve(find func(id string) (interface{}, bool)) error {
+	if d.lockID == "" {
+		return nil
+	}
+
+	o, ok := find(d.lockID)
+	if !ok {
+		return fmt.Errorf("no object with the id %q", d.lockID)
+	}
+
+	k, ok := o.(interface{ Lock() *Lock })
+	if !ok {
+		return fmt.Errorf("%q can't lock a door", d.lockID)
+	}
+
+	WithLock(k.Lock())(d)
+
+	return nil
+}
@@ -42,7 +42,7 @@ func newInteractable(msgType string, zone r.Rectangle, oo ...Option) *interactab
 	return i
 }
 
-func (i *interactable) Add(w *physics.SpatialHashmap) {
+func (i *interactable) Add(w physics.Broadphase) {
 	if i.collider != nil {
 		w.Insert(i.collider)
 	}
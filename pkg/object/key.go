@@ -22,6 +22,8 @@ type Key struct {
 	lock     *Lock
 	msgType  string
 	pickedUp bool
+	// handlerID is the handler that picks up the key.
+	handlerID msg.MessageHandlerID
 
 	*common.BasicEntity
 }
@@ -58,6 +60,9 @@ func NewKey(dest r.Vector2) (*Key, error) {
 		float32(k.Ase.FrameBoundaries().Width), float32(k.Ase.FrameBoundaries().Height),
 		k.lock.mailbox, k.msgType,
 	)
+	// Only the player can pick up keys.
+	k.zone.SetLayer(physics.LayerPickup)
+	k.zone.SetMask(physics.LayerPlayer)
 
 	return k, nil
 }
@@ -70,11 +75,14 @@ func (k *Key) Lock() *Lock {
 
 // Add is a custom adding function to add the key's zone to the spatial hash
 // and then create a handler with its mailbox.
-func (k *Key) Add(w *physics.SpatialHashmap) {
+func (k *Key) Add(w physics.Broadphase) {
 	w.Insert(k.zone)
 
-	// Create a listener in the key's mailbox to listen for collisions.
-	k.lock.mailbox.ListenOnce(k.msgType, func(m msg.Message) {
+	// Create a listener in the key's mailbox to listen for anything walking
+	// into the key.
+	enter := physics.Enter.Of(k.msgType)
+
+	k.handlerID = k.lock.mailbox.Listen(enter, func(m msg.Message) {
 		// Cast the message as a zone message.
 		if zm, ok := m.(*physics.ZoneMessage); ok {
 			// If the entity that's colliding with the zone is a player.
@@ -82,6 +90,7 @@ func (k *Key) Add(w *physics.SpatialHashmap) {
 				// remove zone.
 				w.Remove(k.zone)
 				k.pickedUp = true
+				k.lock.mailbox.StopListen(enter, k.handlerID)
 
 				// Send a message to the lock that it's now unlocked.
 				k.lock.mailbox.Dispatch(
@@ -92,6 +101,12 @@ func (k *Key) Add(w *physics.SpatialHashmap) {
 	})
 }
 
+// Unload stops the key from being picked up and frees its sprite.
+func (k *Key) Unload() {
+	k.lock.mailbox.StopListen(physics.Enter.Of(k.msgType), k.handlerID)
+	k.BasicEntity.Unload()
+}
+
 // Draw is used to draw the key's sprite.
 func (k *Key) Draw() {
 	if k.pickedUp == false {
@@ -10,13 +10,23 @@ type Lock struct {
 	mailbox *msg.MessageManager
 	msgType string
 	locked  bool
+
+	// handlerID is the handler that unlocks the lock once it's applied.
+	handlerID msg.MessageHandlerID
 }
 
 func (l *Lock) applyLock(ll *Lock) {
 	*l = *ll
 
 	// Create a mailbox handler to unlock the door.
-	l.mailbox.ListenOnce(l.msgType, func(m msg.Message) {
+	l.handlerID = l.mailbox.ListenOnce(l.msgType, func(m msg.Message) {
 		l.locked = false
 	})
 }
+
+// stopListen removes the handler that unlocks the lock, if it has one.
+func (l *Lock) stopListen() {
+	if l.mailbox != nil && l.handlerID != 0 {
+		l.mailbox.StopListen(l.msgType, l.handlerID)
+	}
+}
@@ -25,6 +25,8 @@ var (
 // a sprite, facing directions, changeable color, a rigidbody, and a space.
 type Actor struct {
 	Facing common.Direction
+	// Knockback is how hard the actor is pushed away from whatever damaged it.
+	Knockback float32
 
 	Rigidbody *Body
 	Space     *Space
@@ -34,7 +36,13 @@ type Actor struct {
 
 // NewActor returns a basic entity that loads in the sprite
 // based on the given spritesheet. Also creates and adds the rigidbody.
-func NewActor(collision *Space, solids *SpatialHashmap, maxSpeed r.Vector2, ase *aseprite.File) (*Actor, error) {
+func NewActor(collision *Space, solids Broadphase, maxSpeed r.Vector2, ase *aseprite.File) (*Actor, error) {
+	return NewActorWith(collision, solids, maxSpeed, ase, common.Assets)
+}
+
+// NewActorWith returns an actor whose sprite is loaded by the sprite loader
+// given.
+func NewActorWith(collision *Space, solids Broadphase, maxSpeed r.Vector2, ase *aseprite.File, sprites common.SpriteLoader) (*Actor, error) {
 	b := &Actor{
 		Facing:    common.Right,
 		Rigidbody: NewBody(collision, solids, maxSpeed),
@@ -42,7 +50,7 @@ func NewActor(collision *Space, solids *SpatialHashmap, maxSpeed r.Vector2, ase
 	}
 
 	var err error
-	b.BasicEntity, err = common.NewBasicEntity(ase)
+	b.BasicEntity, err = common.NewBasicEntityWith(ase, sprites)
 	if err != nil {
 		return nil, fmt.Errorf("basic entity: %w", err)
 	}
@@ -59,8 +67,11 @@ func (b *Actor) ID() uint64 {
 	return b.Rigidbody.ID()
 }
 
-func (b *Actor) Add(w *SpatialHashmap) {
-	w.InsertMoveables(b)
+// Add inserts the actor into the broadphase. The rigidbody keeps the actor
+// updated in the broadphase whenever it moves.
+func (b *Actor) Add(w Broadphase) {
+	b.Rigidbody.handle = b
+	w.Insert(b)
 }
 
 func (b *Actor) Velocity() r.Vector2 {
@@ -76,8 +87,27 @@ func (b *Actor) AddVelocity(x, y float32) {
 	b.Rigidbody.velocity = b.Rigidbody.velocity.Add(r.NewVector2(x, y))
 }
 
-// TakeDamage doesn't do anything by default.
-func (b *Actor) TakeDamage() {}
+// ApplyForce pushes the actor's rigidbody for the next update.
+func (b *Actor) ApplyForce(force r.Vector2) {
+	b.Rigidbody.ApplyForce(force)
+}
+
+// ApplyImpulse instantly changes the actor's velocity during the next update.
+func (b *Actor) ApplyImpulse(impulse r.Vector2) {
+	b.Rigidbody.ApplyImpulse(impulse)
+}
+
+// ApplyKnockback pushes the actor away from the point given.
+func (b *Actor) ApplyKnockback(from r.Vector2, strength float32) {
+	b.Rigidbody.ApplyKnockback(from, strength)
+}
+
+// TakeDamage knocks the actor away from where the damage came from.
+func (b *Actor) TakeDamage(from r.Vector2) {
+	if b.Knockback > 0 {
+		b.ApplyKnockback(from, b.Knockback)
+	}
+}
 
 // Position returns the position of the rigidbody's collision space.
 func (b *Actor) Position() r.Vector2 {
@@ -108,9 +138,13 @@ func (b *Actor) Draw() {
 		float32(int(b.Facing)*w), float32(h),
 	)
 
+	// Draw the entity in between its last two updates so movement looks
+	// smooth even when the game is updated slower than it's drawn.
+	pos := b.Rigidbody.Interpolate(common.Alpha)
+
 	// Create a destination for the player to be drawn at.
 	dest := r.NewRectangle(
-		b.Rigidbody.Position().X, b.Rigidbody.Position().Y,
+		pos.X, pos.Y,
 		float32(w)*b.Scale, float32(h)*b.Scale,
 	)
 
@@ -8,11 +8,20 @@ import (
 type BasicShape struct {
 	uid  uint64
 	tags []common.Tag
+
+	layer Layer
+	mask  Layer
+
+	material *Material
 }
 
+// NewBasicShape returns a shape on the default layer that collides with
+// everything.
 func NewBasicShape() *BasicShape {
 	return &BasicShape{
-		uid: newID(),
+		uid:   newID(),
+		layer: LayerDefault,
+		mask:  LayerAll,
 	}
 }
 
@@ -66,3 +75,37 @@ func (s *BasicShape) HasTags(tags ...common.Tag) bool {
 
 	return hasTags
 }
+
+// Layer returns the collision layers that the shape is on.
+func (s *BasicShape) Layer() Layer {
+	return s.layer
+}
+
+// Mask returns the collision layers that the shape collides with.
+func (s *BasicShape) Mask() Layer {
+	return s.mask
+}
+
+// SetLayer changes the collision layers that the shape is on.
+func (s *BasicShape) SetLayer(l Layer) {
+	s.layer = l
+}
+
+// SetMask changes the collision layers that the shape collides with.
+func (s *BasicShape) SetMask(l Layer) {
+	s.mask = l
+}
+
+// Material returns what the shape is made of. Shapes without a material
+// use the default one.
+func (s *BasicShape) Material() *Material {
+	if s.material == nil {
+		return DefaultMaterial()
+	}
+	return s.material
+}
+
+// SetMaterial changes what the shape is made of.
+func (s *BasicShape) SetMaterial(m *Material) {
+	s.material = m
+}
@@ -2,6 +2,7 @@ package physics
 
 import (
 	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/msg"
 
 	r "github.com/lachee/raylib-goplus/raylib"
 )
@@ -19,25 +20,71 @@ var (
 // the settings.
 type Body struct {
 	velocity r.Vector2
+	// motion is how far the body is going to move during the current update.
+	motion r.Vector2
+	// previous is the position of the body before the last update and is used
+	// for drawing the body between updates.
+	previous r.Vector2
 
 	gravity  float32
 	onGround bool
+
+	mass float32
+	drag float32
+	// force and impulse are everything applied since the last update.
+	force   r.Vector2
+	impulse r.Vector2
+	// kinematic bodies ignore forces, impulses and gravity.
+	kinematic bool
+
 	maxSpeed r.Vector2
-	solids   *SpatialHashmap
+	solids   Broadphase
+	// handle is what represents the body in the broadphase, which is the
+	// body itself unless something like an Actor added it.
+	handle Transformer
+
+	// ground is the shape that the body is currently standing on.
+	ground Shape
+	// wall and ceiling are the shapes that the body ran into during the last
+	// update, and wallSide is which side of the body the wall is on.
+	wall     Shape
+	wallSide common.Direction
+	ceiling  Shape
+
+	// material is what the body last stood on. It's kept while in the air so
+	// the body doesn't lose its momentum when jumping off of ice.
+	material *Material
+	// dropping are one-way platforms that the body is falling through.
+	// They're ignored until the body has completely cleared them.
+	dropping map[Shape]bool
+	// mover is the shape that's carrying or pushing the body, which is
+	// ignored while the body is moved along with it.
+	mover Shape
+
+	// contacts are the shapes touched during this update and lastContacts
+	// are the ones from the update before. They're compared to figure out
+	// when contacts start and end.
+	contacts     []contact
+	lastContacts []contact
+	mailbox      *msg.MessageManager
 
 	*Space
 }
 
 // NewBody creates a default rigidbody and tags the Rigidbody space as a
 // physics body.
-func NewBody(collision *Space, solids *SpatialHashmap, maxSpeed r.Vector2) *Body {
+func NewBody(collision *Space, solids Broadphase, maxSpeed r.Vector2) *Body {
 	b := &Body{
 		Space:    collision,
 		maxSpeed: maxSpeed,
 		gravity:  common.Config.Game.Gravity,
+		mass:     1,
 		solids:   solids,
+		dropping: make(map[Shape]bool),
+		mailbox:  &msg.MessageManager{},
 	}
 
+	b.handle = b
 	b.AddTags(common.TagPhysicsBody)
 
 	return b
@@ -61,48 +108,277 @@ func (b *Body) SetGravity(g float32) {
 	b.gravity = g
 }
 
+// SetPosition teleports the body to the coordinates provided. The body won't
+// be interpolated from its old position.
+func (b *Body) SetPosition(x, y float32) {
+	b.Space.SetPosition(x, y)
+	b.previous = b.Position()
+	b.solids.Update(b.handle)
+}
+
+// SetSolids takes the body out of its broadphase and makes it collide with
+// another one from then on, which the body still has to be inserted into.
+// Whatever the body was touching is forgotten.
+func (b *Body) SetSolids(solids Broadphase) {
+	b.solids.Remove(b.handle)
+	b.solids = solids
+
+	b.onGround = false
+	b.ground, b.wall, b.ceiling = nil, nil, nil
+	b.dropping = make(map[Shape]bool)
+}
+
+// Interpolate returns the position of the body between its previous and
+// current update, where alpha is 0 for the previous and 1 for the current.
+func (b *Body) Interpolate(alpha float32) r.Vector2 {
+	return b.previous.Lerp(b.Position(), alpha)
+}
+
 // OnGround returns if the collision space is touching ground elements on the
 // Y axis.
 func (b *Body) OnGround() bool {
 	return b.onGround
 }
 
+// Ground returns the shape that the body is standing on, or nil when the
+// body isn't on the ground.
+func (b *Body) Ground() Shape {
+	return b.ground
+}
+
+// OnWall returns which side of the body a wall is on if the body ran into
+// one during the last update.
+func (b *Body) OnWall() (common.Direction, bool) {
+	return b.wallSide, b.wall != nil
+}
+
+// Wall returns the shape that the body ran into from the side, or nil when
+// the body isn't touching a wall.
+func (b *Body) Wall() Shape {
+	return b.wall
+}
+
+// OnCeiling returns if the body hit its head during the last update.
+func (b *Body) OnCeiling() bool {
+	return b.ceiling != nil
+}
+
+// Ceiling returns the shape that the body hit its head on, or nil when the
+// body isn't touching a ceiling.
+func (b *Body) Ceiling() Shape {
+	return b.ceiling
+}
+
+// GroundMaterial returns the material that the body is standing on, or nil
+// when the body isn't on the ground.
+func (b *Body) GroundMaterial() *Material {
+	if b.ground == nil {
+		return nil
+	}
+	return b.ground.Material()
+}
+
+// DropThrough makes the body fall through the one-way platform given, which
+// is ignored until the body has fully cleared it. Returns false if the shape
+// isn't a one-way platform.
+func (b *Body) DropThrough(s Shape) bool {
+	if s == nil || !isOneWay(s) {
+		return false
+	}
+
+	b.dropping[s] = true
+
+	if b.ground == s {
+		b.onGround = false
+		b.ground = nil
+	}
+
+	return true
+}
+
+// Mailbox returns the mailbox that the body sends its ContactMessages to.
+// Listen to Enter.Of(msg.Contact) and so on to hear about them.
+func (b *Body) Mailbox() *msg.MessageManager {
+	return b.mailbox
+}
+
+// land marks the body as standing on the shape provided.
+func (b *Body) land(s Shape) {
+	b.onGround = true
+	b.ground = s
+	b.material = s.Material()
+}
+
 // Update checks for collisions in the world against the colliders in the
 // rigidbody space and adds gravity.
+// Collisions are swept, so the body can't tunnel through thin shapes no
+// matter how fast it's moving.
+//
+// Velocity is in units per second, so the body moves the same distance over
+// time no matter what rate it's updated at.
 func (b *Body) Update(dt float32) {
-	b.velocity.Y += b.gravity * dt
+	b.previous = b.Position()
 
-	b.maxVelocityCheck()
+	// Ride along with whatever the body is standing on if it's moving.
+	if k, ok := b.ground.(Kinematic); ok {
+		b.moveAlong(k.Delta(), b.ground)
+	}
 
-	if b.velocity.Y < -(b.gravity * dt) {
-		b.onGround = false
+	b.integrate(dt)
+
+	if !b.kinematic {
+		b.velocity.Y += b.gravity * dt
 	}
 
+	b.maxVelocityCheck()
+
+	// The body has to touch the ground again during this update to stay on
+	// it, otherwise walking off of a ledge would leave it on the ground.
+	b.onGround = false
+	b.ground = nil
+
+	b.wall, b.wallSide, b.ceiling = nil, 0, nil
+
 	b.ResolveForces(dt)
 
-	b.Move(b.velocity.X, b.velocity.Y)
+	b.Move(b.motion.X, b.motion.Y)
+	b.solids.Update(b.handle)
+
+	b.clearDropping()
+	b.flushContacts()
+}
+
+// clearDropping stops ignoring platforms that the body has fallen through or
+// moved away from.
+func (b *Body) clearDropping() {
+	min, max := b.Position(), b.MaxPosition()
+
+	for s := range b.dropping {
+		below := min.Y >= s.MaxPosition().Y
+		beside := max.X <= s.Position().X || min.X >= s.MaxPosition().X
+
+		if below || beside {
+			delete(b.dropping, s)
+		}
+	}
+}
+
+// moveAlong moves the body by delta while it's carried or pushed by the
+// mover. Anything else in the way stops the body just like when it moves by
+// itself, but what the body is standing on is kept.
+func (b *Body) moveAlong(delta r.Vector2, mover Shape) {
+	if delta.X == 0 && delta.Y == 0 {
+		return
+	}
+
+	onGround, ground, material := b.onGround, b.ground, b.material
+
+	b.motion = delta
+	b.mover = mover
+	b.resolve()
+	b.mover = nil
+
+	b.onGround, b.ground, b.material = onGround, ground, material
+
+	b.Move(b.motion.X, b.motion.Y)
 }
 
 // ResolveForces loops through the collision shapes and checks if they are
 // currently colliding with anything with the given velocity. If so then
 // the velocity is manipulated so that the potential collision will never happen.
 func (b *Body) ResolveForces(dt float32) {
+	b.applyFriction(dt)
+	before := b.velocity
+
+	// Collisions are resolved using the distance travelled in this update.
+	b.motion = b.velocity.Scale(dt)
+	b.resolve()
+
+	// Carry whatever was left of the motion back into the velocity so
+	// collisions stop the body.
+	if dt > 0 {
+		b.velocity = b.motion.Scale(1 / dt)
+	}
+
+	b.bounce(before)
+}
+
+// resolve cuts the motion short wherever any of the colliders would run into
+// something.
+func (b *Body) resolve() {
 	// Limit the player to touching one object on each axis at a time.
 	// This means that numbers won't get messed up when touching two
 	// ground objects at the same time.
-	// var colx, coly bool
 	var col colCheck
 
 	for i := range *b.Space {
-		collider := (*b.Space)[i].(*Rectangle).Rectangle
-		tmpXRec := collider.Move(b.velocity.X, 0)
-		tmpYRec := collider.Move(0, b.velocity.Y)
-		original := b.velocity
+		rec, ok := (*b.Space)[i].(*Rectangle)
+		if !ok {
+			// Circles and polygons are pushed out of whatever they hit instead.
+			b.resolveHull((*b.Space)[i])
+			continue
+		}
+
+		collider := rec.Rectangle
+		tmpXRec := collider.Move(b.motion.X, 0)
+		tmpYRec := collider.Move(0, b.motion.Y)
+		original := b.motion
 
-		// Get all possible collision boxes.
-		possible := b.solids.Retrieve(collider.Move(b.velocity.X, b.velocity.Y))
+		// Get all possible collision boxes along the whole path of the
+		// collider and not just where it ends up. Otherwise shapes thinner
+		// than the velocity could be skipped over entirely.
+		possible := b.solids.RetrieveLayers(
+			sweptBounds(collider, b.motion), (*b.Space)[i].Mask(),
+		)
 
-		b.resolveShapes(col, original, collider, tmpXRec, tmpYRec, possible...)
+		col = b.resolveShapes(col, original, collider, tmpXRec, tmpYRec, possible...)
+	}
+}
+
+// applyFriction slows the body down on the X axis using the friction of the
+// material that it's on.
+func (b *Body) applyFriction(dt float32) {
+	m := b.material
+	if m == nil {
+		m = DefaultMaterial()
+	}
+
+	friction := m.Friction * dt
+
+	switch {
+	case b.velocity.X > friction:
+		b.velocity.X -= friction
+	case b.velocity.X < -friction:
+		b.velocity.X += friction
+	default:
+		b.velocity.X = 0
+	}
+}
+
+// bounce sends the body back off of any bouncy shapes that it hit during this
+// update. before is the velocity from before the collisions stopped the body.
+func (b *Body) bounce(before r.Vector2) {
+	for _, c := range b.contacts {
+		if _, ok := c.shape.(*Zone); ok || (c.normal.X == 0 && c.normal.Y == 0) {
+			continue
+		}
+
+		restitution := c.shape.Material().Restitution
+		into := before.DotProduct(c.normal)
+
+		if restitution <= 0 || -into < bounceThreshold {
+			continue
+		}
+
+		// Replace the speed along the normal with the bounced speed.
+		along := b.velocity.DotProduct(c.normal)
+		b.velocity = b.velocity.Add(c.normal.Scale(-into*restitution - along))
+
+		// The body is leaving the ground again.
+		if c.normal.Y < 0 {
+			b.onGround = false
+			b.ground = nil
+		}
 	}
 }
 
@@ -115,6 +391,12 @@ func (b *Body) resolveShapes(col colCheck, original r.Vector2, collider, tmpXRec
 			}
 		}
 
+		// Skip platforms that the body is dropping through, whatever is
+		// moving it and shapes on layers that the body doesn't collide with.
+		if s, ok := p.(Shape); ok && (b.dropping[s] || s == b.mover || !Collides(b.Space, s)) {
+			continue
+		}
+
 		switch t := p.(type) {
 		case *Actor:
 			b.resolveSpace(
@@ -131,41 +413,70 @@ func (b *Body) resolveShapes(col colCheck, original r.Vector2, collider, tmpXRec
 		case *Rectangle:
 			// Resolve the X collisions.
 			col.SetX(b.resolveRectangle(
-				t, tmpXRec, col.X(), b.velocity.X, r.NewVector2(1, 0),
+				t, t, collider, col.X(), r.NewVector2(1, 0),
 			))
 			// Resolve the Y collisions.
 			col.SetY(b.resolveRectangle(
-				t, tmpYRec, col.Y(), b.velocity.Y, r.NewVector2(0, 1),
+				t, t, collider, col.Y(), r.NewVector2(0, 1),
+			))
+		// Moving platforms are solid just like rectangles.
+		case *MovingPlatform:
+			col.SetX(b.resolveRectangle(
+				t.Rectangle, t, collider, col.X(), r.NewVector2(1, 0),
+			))
+			col.SetY(b.resolveRectangle(
+				t.Rectangle, t, collider, col.Y(), r.NewVector2(0, 1),
 			))
 		// SlopePlatform is just three slopes.
 		case *SlopePlatform:
+			// One-way slope platforms can be jumped through from below.
+			if t.OneWay() && b.motion.Y < 0 {
+				break
+			}
+
 			if t.Overlaps(tmpYRec) {
 				// Ignore coly check, because slopes take higher priority.
 				// Resolve all the slopes within the platform.
-				col.SetY(b.resolveSlope(t.landingZone1, tmpYRec, original))
-				col.SetY(b.resolveSlope(t.landingZone2, tmpYRec, original))
-				col.SetY(b.resolveSlope(t.slope, tmpYRec, original))
+				col.SetY(b.resolveSlope(t.landingZone1, t, tmpYRec, original))
+				col.SetY(b.resolveSlope(t.landingZone2, t, tmpYRec, original))
+				col.SetY(b.resolveSlope(t.slope, t, tmpYRec, original))
+			} else {
+				// The collider may have fallen through the whole platform.
+				col.SetY(b.sweepSlope(t.landingZone1, t, collider, col.Y()))
+				col.SetY(b.sweepSlope(t.landingZone2, t, collider, col.Y()))
+				col.SetY(b.sweepSlope(t.slope, t, collider, col.Y()))
 			}
 		case *Slope:
 			if t.Overlaps(tmpYRec) {
-				col.SetY(b.resolveSlope(t, tmpYRec, original))
+				col.SetY(b.resolveSlope(t, t, tmpYRec, original))
+			} else {
+				col.SetY(b.sweepSlope(t, t, collider, col.Y()))
 			}
 		case *Platform:
-			if !col.Y() && b.velocity.Y > 0 && t.Overlaps(tmpYRec) {
+			if hit, ok := t.Sweep(collider, r.NewVector2(0, b.motion.Y)); ok {
+				// Land on the platform at the point of contact.
+				col.SetY(true)
+				b.land(t)
+				b.touch(t, r.Rectangle{}, hit.Normal)
+				b.motion.Y *= hit.Time
+			} else if !col.Y() && b.motion.Y > 0 && t.Overlaps(tmpYRec) {
 				overlap := t.Rectangle.GetOverlapRec(tmpYRec)
 
 				// If the overlapped rectangle is more than halfway down
 				// the entity, then count this as a collision.
 				if overlap.Y > tmpYRec.Center().Y {
 					col.SetY(true)
-					b.onGround = true
-					b.velocity.Y -= overlap.Height
+					b.land(t)
+					b.touch(t, overlap, r.NewVector2(0, -1))
+					b.motion.Y -= overlap.Height
 				}
 			}
+		case *Circle, *ConvexPolygon:
+			b.resolveConvex(t.(Shape), collider)
 		case *Zone:
 			if t.Overlaps(collider) {
 				overlap := t.Rectangle.GetOverlapRec(collider)
-				t.dispatchMessage(b.Space, overlap)
+				b.touch(t, overlap, overlapNormal(t.Rectangle.Rectangle, collider, overlap))
 			}
 		}
 	}
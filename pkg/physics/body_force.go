@@ -0,0 +1,96 @@
+package physics
+
+import (
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// knockbackLift is how much upward push is added to knockbacks, so bodies
+// standing on the ground are knocked into the air instead of along it.
+const knockbackLift = 0.5
+
+// Mass returns how heavy the body is. Heavier bodies are pushed less by the
+// same forces and impulses.
+func (b *Body) Mass() float32 {
+	return b.mass
+}
+
+// SetMass changes how heavy the body is. Masses that aren't above zero are
+// ignored.
+func (b *Body) SetMass(mass float32) {
+	if mass > 0 {
+		b.mass = mass
+	}
+}
+
+// Drag returns how much of the body's velocity is lost every second.
+func (b *Body) Drag() float32 {
+	return b.drag
+}
+
+// SetDrag changes how much of the body's velocity is lost every second,
+// where 0 is no drag at all and 1 stops the body within a second.
+func (b *Body) SetDrag(drag float32) {
+	b.drag = drag
+}
+
+// Kinematic returns if the body ignores forces, impulses and gravity.
+func (b *Body) Kinematic() bool {
+	return b.kinematic
+}
+
+// SetKinematic makes the body ignore forces, impulses and gravity. Kinematic
+// bodies still collide, but only move by their velocity.
+func (b *Body) SetKinematic(kinematic bool) {
+	b.kinematic = kinematic
+}
+
+// ApplyForce pushes the body for the next update. Forces are in units per
+// second squared times mass, so they have to be applied every update to keep
+// pushing the body.
+func (b *Body) ApplyForce(force r.Vector2) {
+	b.force = b.force.Add(force)
+}
+
+// ApplyImpulse instantly changes the body's velocity by the impulse divided
+// by its mass during the next update.
+func (b *Body) ApplyImpulse(impulse r.Vector2) {
+	b.impulse = b.impulse.Add(impulse)
+}
+
+// ApplyKnockback pushes the body away from the point given.
+func (b *Body) ApplyKnockback(from r.Vector2, strength float32) {
+	min, max := b.Position(), b.MaxPosition()
+	center := r.NewVector2((min.X+max.X)/2, (min.Y+max.Y)/2)
+
+	dir := center.Subtract(from)
+	if dir.X == 0 && dir.Y == 0 {
+		dir = r.NewVector2(0, -1)
+	}
+	dir = dir.Normalize()
+	dir.Y -= knockbackLift
+
+	b.ApplyImpulse(dir.Normalize().Scale(strength))
+}
+
+// integrate adds the forces and impulses applied since the last update to the
+// velocity and slows the body down by its drag.
+func (b *Body) integrate(dt float32) {
+	force, impulse := b.force, b.impulse
+	b.force, b.impulse = r.Vector2{}, r.Vector2{}
+
+	if b.kinematic {
+		return
+	}
+
+	b.velocity = b.velocity.
+		Add(force.Scale(dt / b.mass)).
+		Add(impulse.Scale(1 / b.mass))
+
+	if b.drag > 0 {
+		keep := 1 - b.drag*dt
+		if keep < 0 {
+			keep = 0
+		}
+		b.velocity = b.velocity.Scale(keep)
+	}
+}
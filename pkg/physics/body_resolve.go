@@ -1,6 +1,8 @@
 package physics
 
 import (
+	"math"
+
 	r "github.com/lachee/raylib-goplus/raylib"
 )
 
@@ -13,32 +15,89 @@ func (b *Body) resolveSpace(space *Space, fn func(...interface{})) {
 	fn(tt...)
 }
 
+// resolveRectangle stops the collider from moving into the rectangle on the
+// axis given. owner is the shape that the rectangle belongs to, which the body
+// lands on.
 func (b *Body) resolveRectangle(
-	rec *Rectangle, tmpRec r.Rectangle, prevCol bool, velocity float32, velocityMult r.Vector2,
+	rec *Rectangle, owner Shape, collider r.Rectangle, prevCol bool, axis r.Vector2,
 ) bool {
+	// Vector2.Multiply scales X by the other vector's Y, so multiply by hand.
+	delta := r.NewVector2(b.motion.X*axis.X, b.motion.Y*axis.Y)
+
+	// Sweep the collider along the axis to find the first moment it touches
+	// the rectangle. Every rectangle is swept even after a collision on this
+	// axis, since a closer rectangle should always win.
+	if hit, ok := rec.Sweep(collider, delta); ok {
+		if hit.Normal.Y < 0 {
+			b.land(owner)
+		}
+		b.touch(owner, r.Rectangle{}, hit.Normal)
+
+		// Only allow the collider to travel up to the point of contact.
+		b.motion.X -= delta.X * (1 - hit.Time)
+		b.motion.Y -= delta.Y * (1 - hit.Time)
+
+		return true
+	}
+
+	tmpRec := collider.Move(delta.X, delta.Y)
+
 	// If the player hasn't collided with anything on the axis yet and is
-	// overlapping with the physics rectangle.
+	// overlapping with the physics rectangle. This only happens when the
+	// collider was already inside of the rectangle before moving.
 	if !prevCol && rec.Overlaps(tmpRec) {
 		overlap := rec.Rectangle.GetOverlapRec(tmpRec)
-		prevCol = true
 
-		if velocity*velocityMult.Y > 0 {
-			b.onGround = true
+		// Only push the collider out along the axis that it's the least
+		// inside of the rectangle on, which the pass for the other axis does
+		// otherwise. This stops a collider that sank into the ground from
+		// being pushed back while it walks along it.
+		if (axis.X != 0 && overlap.Height <= overlap.Width) ||
+			(axis.Y != 0 && overlap.Width < overlap.Height) {
+			return prevCol
 		}
 
-		if velocity > 0 {
-			b.velocity.X -= (overlap.Width * velocityMult.X)
-			b.velocity.Y -= (overlap.Height * velocityMult.Y)
-		} else {
-			b.velocity.X += (overlap.Width * velocityMult.X)
-			b.velocity.Y += (overlap.Height * velocityMult.Y)
+		// The collider is pushed back against where it's moving, or out of
+		// the closest side of the rectangle when it isn't moving on the axis.
+		dir := sign(delta.X + delta.Y)
+		if dir == 0 {
+			dir = 1
+			if tmpRec.Center().DotProduct(axis) > rec.Rectangle.Center().DotProduct(axis) {
+				dir = -1
+			}
 		}
+		prevCol = true
+
+		if dir*axis.Y > 0 {
+			b.land(owner)
+		}
+		b.touch(owner, overlap, axis.Scale(-dir))
+
+		b.motion.X -= dir * overlap.Width * axis.X
+		b.motion.Y -= dir * overlap.Height * axis.Y
 	}
 
 	return prevCol
 }
 
-func (b *Body) resolveSlope(t *Slope, tmpYRec r.Rectangle, original r.Vector2) bool {
+// sweepSlope catches slopes that the collider would pass completely through in
+// a single update, which the overlap check in resolveSlope can't see.
+// owner is the shape that the slope belongs to, which the body lands on.
+func (b *Body) sweepSlope(t *Slope, owner Shape, collider r.Rectangle, prevCol bool) bool {
+	hit, ok := t.Sweep(collider, r.NewVector2(0, b.motion.Y))
+	if !ok {
+		return prevCol
+	}
+
+	// Stop the collider right on top of the slope.
+	b.land(owner)
+	b.touch(owner, r.Rectangle{}, hit.Normal)
+	b.motion.Y *= hit.Time
+
+	return true
+}
+
+func (b *Body) resolveSlope(t *Slope, owner Shape, tmpYRec r.Rectangle, original r.Vector2) bool {
 	// Get the intersection points given a temporary Y rectangle.
 	intersections := t.GetIntersectionPoints(
 		NewRectangle(tmpYRec.X, tmpYRec.Y, tmpYRec.Width, tmpYRec.Height),
@@ -59,8 +118,184 @@ func (b *Body) resolveSlope(t *Slope, tmpYRec r.Rectangle, original r.Vector2) b
 	overlap := colBox.GetOverlapRec(tmpYRec)
 
 	// Since there was a collision, set the onGround to true right away.
-	b.onGround = true
-	b.velocity.Y = original.Y - overlap.Height
+	b.land(owner)
+	b.touch(owner, overlap, r.NewVector2(0, -1))
+	b.motion.Y = original.Y - overlap.Height
 
 	return true
 }
+
+// resolveConvex pushes the collider out of a circle or polygon that it runs
+// into. The collider is moved in small steps like resolveHull does, so it
+// can't pass through the shape when it's moving fast.
+func (b *Body) resolveConvex(s Shape, collider r.Rectangle) {
+	target := hullOf(s)
+
+	steps := hullSteps(collider.Width, collider.Height, b.motion)
+	step := b.motion.Scale(1 / float32(steps))
+
+	moved := collider
+	for i := 0; i < steps; i++ {
+		moved = moved.Move(step.X, step.Y)
+
+		mtv, ok := collideHulls(rectHull(moved), target)
+		if !ok {
+			continue
+		}
+
+		moved = moved.Move(mtv.X, mtv.Y)
+		b.separate(s, mtv)
+
+		// Stop the rest of the steps from moving into the surface.
+		normal := mtv.Normalize()
+		if d := step.DotProduct(normal); d < 0 {
+			step = step.Subtract(normal.Scale(d))
+		}
+	}
+
+	b.motion = moved.Position().Subtract(collider.Position())
+}
+
+// hullSteps returns how many steps the motion is split into so a collider of
+// the size given never moves more than half of its size at a time, otherwise
+// it could pass through shapes thinner than it is.
+func hullSteps(width, height float32, motion r.Vector2) int {
+	size := float32(math.Min(float64(width), float64(height))) / 2
+	if size <= 0 {
+		return 1
+	}
+
+	steps := int(math.Ceil(float64(motion.Length() / size)))
+	if steps < 1 {
+		return 1
+	}
+
+	return steps
+}
+
+// hullCandidate is a shape that a circle or polygon collider may hit along
+// with the shape that it belongs to, which the body lands on.
+type hullCandidate struct {
+	shape Shape
+	owner Shape
+}
+
+// resolveHull moves a collider that isn't a rectangle along the motion in
+// small steps and pushes it out of anything that it overlaps using the
+// separating axis theorem. The collider is put back where it started once the
+// motion is worked out, since the whole body is moved afterwards.
+func (b *Body) resolveHull(collider Shape) {
+	start := collider.Position()
+
+	candidates := b.hullCandidates(nil, nil, b.solids.RetrieveLayers(
+		sweptBounds(boundsOf(collider), b.motion), collider.Mask(),
+	)...)
+
+	steps := hullSteps(collider.Width(), collider.Height(), b.motion)
+	step := b.motion.Scale(1 / float32(steps))
+
+	for i := 0; i < steps; i++ {
+		collider.Move(step.X, step.Y)
+
+		for _, c := range candidates {
+			mtv, ok := Collide(collider, c.shape)
+			if !ok {
+				continue
+			}
+
+			if z, ok := c.shape.(*Zone); ok {
+				bounds := boundsOf(collider)
+				overlap := z.Rectangle.GetOverlapRec(bounds)
+				b.touch(z, overlap, overlapNormal(z.Rectangle.Rectangle, bounds, overlap))
+				continue
+			}
+
+			if !blocks(c.shape, mtv, step) {
+				continue
+			}
+
+			collider.Move(mtv.X, mtv.Y)
+			b.separate(c.owner, mtv)
+
+			// Stop the rest of the steps from moving into the surface.
+			normal := mtv.Normalize()
+			if d := step.DotProduct(normal); d < 0 {
+				step = step.Subtract(normal.Scale(d))
+			}
+		}
+	}
+
+	b.motion = collider.Position().Subtract(start)
+	collider.SetPosition(start.X, start.Y)
+}
+
+// hullCandidates flattens the possible shapes into the single shapes that a
+// circle or polygon collider can be pushed out of.
+func (b *Body) hullCandidates(owner Shape, out []hullCandidate, possible ...interface{}) []hullCandidate {
+	for _, p := range possible {
+		// Skip from colliding against itself.
+		if ss, ok := p.(Entity); ok {
+			if ss.ID() == b.ID() {
+				continue
+			}
+		}
+
+		if s, ok := p.(Shape); ok && (b.dropping[s] || s == b.mover || !Collides(b.Space, s)) {
+			continue
+		}
+
+		switch t := p.(type) {
+		case *Actor:
+			b.resolveSpace(t.Space, func(tt ...interface{}) {
+				out = b.hullCandidates(nil, out, tt...)
+			})
+		case *Space:
+			b.resolveSpace(t, func(tt ...interface{}) {
+				out = b.hullCandidates(owner, out, tt...)
+			})
+		case *SlopePlatform:
+			// One-way slope platforms can be jumped through from below.
+			if t.OneWay() && b.motion.Y < 0 {
+				break
+			}
+
+			b.resolveSpace(t.Space, func(tt ...interface{}) {
+				out = b.hullCandidates(t, out, tt...)
+			})
+		case Shape:
+			o := owner
+			if o == nil {
+				o = t
+			}
+
+			out = append(out, hullCandidate{t, o})
+		}
+	}
+
+	return out
+}
+
+// blocks returns if the shape stops a collider that would be pushed out by
+// the mtv after moving by step. Slopes can only be stood on and platforms can
+// only be landed on from above.
+func blocks(s Shape, mtv, step r.Vector2) bool {
+	switch s.(type) {
+	case *Slope:
+		return mtv.Y < 0
+	case *Platform:
+		// The collider must have been above the platform before this step.
+		return mtv.Y < 0 && mtv.Length() <= step.Y+sweepEpsilon
+	}
+
+	return true
+}
+
+// separate lands the body on the shape if the body was pushed up out of it
+// and records the contact.
+func (b *Body) separate(owner Shape, mtv r.Vector2) {
+	if mtv.Y < 0 && -mtv.Y >= float32(math.Abs(float64(mtv.X))) {
+		b.land(owner)
+	}
+
+	b.touch(owner, r.Rectangle{}, mtv.Normalize())
+}
@@ -0,0 +1,197 @@
+package physics
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+const dt = 1.0 / 60
+
+func TestMain(m *testing.M) {
+	raw, err := ioutil.ReadFile("../../config/settings.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := json.Unmarshal(raw, &common.Config); err != nil {
+		log.Fatal(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// newTestBody creates a body with a rectangle collider and without gravity.
+func newTestBody(solids Broadphase, x, y, w, h float32) *Body {
+	collision := NewSpace()
+	collision.Add(NewRectangle(x, y, w, h))
+
+	b := NewBody(collision, solids, r.NewVector2(10000, 10000))
+	b.SetGravity(0)
+
+	return b
+}
+
+func expectPosition(t *testing.T, b *Body, want r.Vector2) {
+	t.Helper()
+
+	if got := b.Position(); got.Subtract(want).Length() > 0.001 {
+		t.Errorf("body is at %v, want %v", got, want)
+	}
+}
+
+func TestSunkColliderSlides(t *testing.T) {
+	solids := NewSpatialHashmap(6)
+	solids.Insert(NewRectangle(0, 100, 200, 20))
+
+	// The collider is 2 units inside of the floor and walks along it.
+	b := newTestBody(solids, 10, 86, 10, 16)
+	b.SetVelocity(600, 0)
+	b.Update(dt)
+
+	// It's pushed up out of the floor instead of back along it. The floor's
+	// friction takes 30 off of its speed first.
+	expectPosition(t, b, r.NewVector2(19.5, 84))
+}
+
+func TestSunkColliderPushedOutOfWall(t *testing.T) {
+	solids := NewSpatialHashmap(6)
+	solids.Insert(NewRectangle(100, 0, 20, 200))
+
+	// The collider is 2 units inside of the wall and falls along it.
+	b := newTestBody(solids, 92, 50, 10, 16)
+	b.SetVelocity(0, 60)
+	b.Update(dt)
+
+	expectPosition(t, b, r.NewVector2(90, 51))
+}
+
+func TestPlatformCarriesRiderIntoWall(t *testing.T) {
+	solids := NewSpatialHashmap(6)
+	solids.Insert(NewRectangle(100, 0, 10, 100))
+
+	platform := NewMovingPlatform(60, 10, 600, r.NewVector2(0, 50), r.NewVector2(200, 50))
+	platform.Add(solids)
+
+	rider := newTestBody(solids, 40, 34, 10, 16)
+	rider.SetGravity(600)
+	rider.Update(dt)
+
+	if rider.Ground() != platform {
+		t.Fatalf("rider is standing on %v, want the platform", rider.Ground())
+	}
+
+	// The platform keeps going under the rider after it stops at the wall.
+	for i := 0; i < 8; i++ {
+		platform.Update(dt)
+		rider.Update(dt)
+
+		if x := rider.Position().X; x > 90 {
+			t.Fatalf("rider was carried into the wall at %v", x)
+		}
+	}
+
+	expectPosition(t, rider, r.NewVector2(90, 34))
+}
+
+func TestPlatformPushesBody(t *testing.T) {
+	solids := NewSpatialHashmap(6)
+	solids.Insert(NewRectangle(150, 0, 10, 100))
+
+	platform := NewMovingPlatform(60, 10, 600, r.NewVector2(0, 50), r.NewVector2(200, 50))
+	platform.Add(solids)
+
+	// A plain body in front of the platform is pushed until it hits the wall.
+	b := newTestBody(solids, 80, 45, 10, 16)
+	solids.Insert(b)
+
+	for i := 0; i < 10; i++ {
+		platform.Update(dt)
+	}
+
+	expectPosition(t, b, r.NewVector2(140, 45))
+}
+
+func TestFastColliderHitsConvexShapes(t *testing.T) {
+	shapes := []Shape{
+		NewCircle(5, 100, 4),
+		NewConvexPolygon(r.NewVector2(0, 96), r.NewVector2(10, 96), r.NewVector2(5, 104)),
+	}
+
+	for _, s := range shapes {
+		solids := NewSpatialHashmap(6)
+		solids.Insert(s)
+
+		// The collider would move from above the shape to below it in a
+		// single update.
+		b := newTestBody(solids, 0, 0, 10, 16)
+		b.SetVelocity(0, 9000)
+		b.Update(dt)
+
+		if b.Ground() != s {
+			t.Errorf("%T: body passed through it to %v", s, b.Position())
+		}
+	}
+}
+
+func TestShapeCastHitsConvexShapes(t *testing.T) {
+	shapes := []Shape{
+		NewCircle(5, 100, 4),
+		NewConvexPolygon(r.NewVector2(0, 96), r.NewVector2(10, 96), r.NewVector2(5, 104)),
+	}
+
+	for _, s := range shapes {
+		solids := NewSpatialHashmap(6)
+		solids.Insert(s)
+
+		hit, ok := solids.ShapeCast(r.NewRectangle(0, 0, 10, 16), r.NewVector2(0, 200), nil)
+		if !ok || hit.Shape != s {
+			t.Errorf("%T: shape cast passed through it", s)
+			continue
+		}
+
+		// The rectangle stops with its bottom on top of the shape.
+		if bottom := hit.Point.Y + 16; bottom < 95.99 || bottom > 96.01 {
+			t.Errorf("%T: shape cast stopped with its bottom at %v, want 96", s, bottom)
+		}
+		if hit.Normal.Y >= 0 {
+			t.Errorf("%T: normal is %v, want it pointing up", s, hit.Normal)
+		}
+	}
+}
+
+func TestCollideCirclesWithSameCenter(t *testing.T) {
+	mtv, ok := Collide(NewCircle(10, 10, 3), NewCircle(10, 10, 2))
+	if !ok {
+		t.Fatal("circles with the same center aren't overlapping")
+	}
+
+	if want := r.NewVector2(0, -5); mtv != want {
+		t.Errorf("mtv = %v, want %v", mtv, want)
+	}
+}
+
+func TestCheckLayers(t *testing.T) {
+	if err := checkLayers(); err != nil {
+		t.Fatalf("settings.json: %v", err)
+	}
+
+	layers := common.Config.Physics.Layers
+	defer func() { common.Config.Physics.Layers = layers }()
+
+	common.Config.Physics.Layers = []string{"default", "enemy", "player"}
+	if err := checkLayers(); err == nil {
+		t.Error("swapped layers passed the check")
+	}
+
+	common.Config.Physics.Layers = layers[:2]
+	if err := checkLayers(); err == nil {
+		t.Error("missing layers passed the check")
+	}
+}
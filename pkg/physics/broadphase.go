@@ -0,0 +1,324 @@
+package physics
+
+import (
+	"fmt"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ Broadphase = &SpatialHashmap{}
+	_ Broadphase = &Quadtree{}
+	_ Broadphase = &SweepAndPrune{}
+)
+
+// Broadphase is a structure that quickly finds which objects in the world are
+// close to each other. Bodies only check their collisions against what the
+// broadphase gives back, so the implementation can be chosen per level size.
+type Broadphase interface {
+	// InsertI inserts objects of any type. SpatialAdders add themselves.
+	InsertI(objects ...interface{}) error
+	// Insert adds transformers to the broadphase.
+	Insert(t ...Transformer)
+	// Remove takes a transformer out of the broadphase.
+	Remove(t Transformer)
+	// Update moves a transformer to wherever it is now after it has moved.
+	Update(t Transformer)
+
+	// Retrieve returns every object touching the transformer's bounds with
+	// no duplicates. If the transformer is Layered then its mask is used.
+	Retrieve(t Transformer) []interface{}
+	// RetrieveLayers is Retrieve only returning objects on the mask's layers.
+	RetrieveLayers(t Transformer, mask Layer) []interface{}
+
+	// Raycast returns the nearest shape that the ray hits within maxDist.
+	// Nothing is hit when maxDist is negative or isn't finite.
+	Raycast(origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool)
+	ShapeCast(rec r.Rectangle, delta r.Vector2, filter QueryFilter) (RayHit, bool)
+
+	// Stats returns the statistics of the last query.
+	Stats() QueryStats
+	// Len returns the amount of objects in the broadphase.
+	Len() int
+	// Clear removes everything from the broadphase.
+	Clear()
+}
+
+// SpatialAdder is a custom adder to a broadphase.
+type SpatialAdder interface {
+	Add(w Broadphase) // custom add function
+}
+
+// QueryStats describes how much work a single broadphase query did.
+type QueryStats struct {
+	// Nodes is the amount of cells, tree nodes or sorted entries visited.
+	Nodes int
+	// Candidates is the amount of objects that were looked at.
+	Candidates int
+	// Duplicates is the amount of objects skipped for already being found.
+	Duplicates int
+	// Results is the amount of objects returned.
+	Results int
+}
+
+func (q QueryStats) String() string {
+	return fmt.Sprintf(
+		"nodes: %d, candidates: %d, duplicates: %d, results: %d",
+		q.Nodes, q.Candidates, q.Duplicates, q.Results,
+	)
+}
+
+// proxy is an object stored in a broadphase along with the bounds it had
+// when it was inserted or last updated.
+type proxy struct {
+	object interface{}
+	bounds r.Rectangle
+
+	// stamp is the last query that found the proxy and is used to skip
+	// duplicates without needing a set for every query.
+	stamp uint64
+	// index is where the proxy is in the proxy set's list.
+	index int
+
+	// keys are the hashmap cells that the proxy is in.
+	keys []point
+	// node is the quadtree node that holds the proxy.
+	node *quadNode
+}
+
+// proxySet keeps track of the proxies for every object in a broadphase and
+// does the bookkeeping that all the broadphases share.
+type proxySet struct {
+	proxies map[interface{}]*proxy
+	list    []*proxy
+
+	stamp uint64
+	stats QueryStats
+}
+
+// add creates a proxy for the object. If the object was already added then
+// nil is returned.
+func (ps *proxySet) add(obj Transformer) *proxy {
+	if ps.proxies == nil {
+		ps.proxies = make(map[interface{}]*proxy)
+	}
+	if _, ok := ps.proxies[obj]; ok {
+		return nil
+	}
+
+	p := &proxy{
+		object: obj,
+		bounds: boundsOf(obj),
+		index:  len(ps.list),
+	}
+
+	ps.proxies[obj] = p
+	ps.list = append(ps.list, p)
+
+	return p
+}
+
+// remove deletes the object's proxy and returns it, or nil if the object
+// was never added.
+func (ps *proxySet) remove(obj Transformer) *proxy {
+	p, ok := ps.proxies[obj]
+	if !ok {
+		return nil
+	}
+
+	// Swap the last proxy into the removed one's place.
+	last := ps.list[len(ps.list)-1]
+	ps.list[p.index] = last
+	last.index = p.index
+	ps.list = ps.list[:len(ps.list)-1]
+
+	delete(ps.proxies, obj)
+
+	return p
+}
+
+func (ps *proxySet) reset() {
+	ps.proxies = make(map[interface{}]*proxy)
+	ps.list = nil
+}
+
+// begin starts a new query.
+func (ps *proxySet) begin() {
+	ps.stamp++
+	ps.stats = QueryStats{}
+}
+
+// visit appends the proxy's object to the results if it's the first time the
+// proxy was seen this query, it touches the bounds and is on the mask's layers.
+func (ps *proxySet) visit(res []interface{}, p *proxy, bounds r.Rectangle, mask Layer) []interface{} {
+	ps.stats.Candidates++
+
+	if p.stamp == ps.stamp {
+		ps.stats.Duplicates++
+		return res
+	}
+	p.stamp = ps.stamp
+
+	if !touching(p.bounds, bounds) || !onLayers(p.object, mask) {
+		return res
+	}
+
+	ps.stats.Results++
+
+	return append(res, p.object)
+}
+
+// all returns every object on the mask's layers.
+func (ps *proxySet) all(mask Layer) []interface{} {
+	ps.begin()
+
+	var res []interface{}
+	for _, p := range ps.list {
+		ps.stats.Nodes++
+		ps.stats.Candidates++
+
+		if onLayers(p.object, mask) {
+			ps.stats.Results++
+			res = append(res, p.object)
+		}
+	}
+
+	return res
+}
+
+// Stats returns the statistics of the last query.
+func (ps *proxySet) Stats() QueryStats {
+	return ps.stats
+}
+
+// Len returns the amount of objects in the broadphase.
+func (ps *proxySet) Len() int {
+	return len(ps.list)
+}
+
+// insertObjects inserts objects of any type into a broadphase.
+func insertObjects(bp Broadphase, objects ...interface{}) error {
+	for i := range objects {
+		switch t := objects[i].(type) {
+		case SpatialAdder:
+			t.Add(bp)
+		case Transformer:
+			bp.Insert(t)
+		default:
+			return fmt.Errorf("invalid insert: %v", t)
+		}
+	}
+
+	return nil
+}
+
+// raycastObjects shoots a ray against every object and returns the closest hit.
+func raycastObjects(objects []interface{}, origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool) {
+	var (
+		best  RayHit
+		found bool
+	)
+
+	best.Distance = maxDist
+
+	for _, obj := range objects {
+		if hit, ok := raycastObject(obj, origin, dir, best.Distance, filter); ok {
+			best = hit
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// raycast is used by broadphases that can't walk a ray through themselves.
+// Every object touching the ray's bounds is checked instead.
+func raycast(bp Broadphase, origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool) {
+	if !validRay(dir, maxDist) {
+		return RayHit{}, false
+	}
+	dir = dir.Normalize()
+
+	bounds := sweptBounds(r.NewRectangle(origin.X, origin.Y, 0, 0), dir.Scale(maxDist))
+
+	return raycastObjects(bp.Retrieve(bounds), origin, dir, maxDist, filter)
+}
+
+// shapeCast sweeps a rectangle by delta through the broadphase and returns the
+// nearest shape that it would hit.
+func shapeCast(bp Broadphase, rec r.Rectangle, delta r.Vector2, filter QueryFilter) (RayHit, bool) {
+	var (
+		best  RayHit
+		found bool
+	)
+
+	length := delta.Length()
+	best.Distance = length
+
+	for _, obj := range bp.Retrieve(sweptBounds(rec, delta)) {
+		hit, ok := sweepObject(obj, rec, delta, filter)
+		if !ok {
+			continue
+		}
+
+		if dist := hit.Time * length; !found || dist < best.Distance {
+			best = RayHit{
+				Point:    rec.Position().Add(delta.Scale(hit.Time)),
+				Normal:   hit.Normal,
+				Distance: dist,
+				Shape:    hit.shape,
+			}
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// boundsOf returns the rectangle that covers the transformer. Slopes return
+// their end points as their positions, so the corners may need to be swapped.
+func boundsOf(t Transformer) r.Rectangle {
+	min, max := t.Position(), t.MaxPosition()
+	if min.X > max.X {
+		min.X, max.X = max.X, min.X
+	}
+	if min.Y > max.Y {
+		min.Y, max.Y = max.Y, min.Y
+	}
+
+	return r.NewRectangle(min.X, min.Y, max.X-min.X, max.Y-min.Y)
+}
+
+// touching is like Overlaps except rectangles that are only touching edges
+// count too, so shapes resting against each other are still found.
+func touching(a, b r.Rectangle) bool {
+	return a.X <= b.X+b.Width && b.X <= a.X+a.Width &&
+		a.Y <= b.Y+b.Height && b.Y <= a.Y+a.Height
+}
+
+// contains returns if the inner rectangle is completely inside the outer one.
+func contains(outer, inner r.Rectangle) bool {
+	return inner.X >= outer.X && inner.Y >= outer.Y &&
+		inner.X+inner.Width <= outer.X+outer.Width &&
+		inner.Y+inner.Height <= outer.Y+outer.Height
+}
+
+// onLayers returns if the object is on any of the mask's layers.
+// Objects without layers are always on them.
+func onLayers(obj interface{}, mask Layer) bool {
+	if mask == LayerAll {
+		return true
+	}
+	if l, ok := obj.(Layered); ok {
+		return l.Layer()&mask != 0
+	}
+	return true
+}
+
+// queryMask returns the mask that a transformer queries with.
+func queryMask(t Transformer) Layer {
+	if l, ok := t.(Layered); ok {
+		return l.Mask()
+	}
+	return LayerAll
+}
@@ -0,0 +1,204 @@
+package physics
+
+import (
+	"math/rand"
+	"testing"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// The benchmarks run on a generated level with thousands of tiles, which is
+// similar to what a real level would look like.
+const (
+	benchTileSize = 16
+	benchWidth    = 400
+	benchHeight   = 60
+	benchMovers   = 50
+	benchSeed     = 1
+)
+
+func newHashmap() Broadphase {
+	return NewSpatialHashmap(6)
+}
+
+func newQuadtree() Broadphase {
+	return NewQuadtree(r.NewRectangle(0, 0, benchWidth*benchTileSize, benchHeight*benchTileSize))
+}
+
+func newSweepAndPrune() Broadphase {
+	return NewSweepAndPrune()
+}
+
+func BenchmarkHashmapInsert(b *testing.B)         { benchInsert(b, newHashmap) }
+func BenchmarkHashmapRetrieve(b *testing.B)       { benchRetrieve(b, newHashmap) }
+func BenchmarkHashmapUpdate(b *testing.B)         { benchUpdate(b, newHashmap) }
+func BenchmarkHashmapRaycast(b *testing.B)        { benchRaycast(b, newHashmap) }
+func BenchmarkQuadtreeInsert(b *testing.B)        { benchInsert(b, newQuadtree) }
+func BenchmarkQuadtreeRetrieve(b *testing.B)      { benchRetrieve(b, newQuadtree) }
+func BenchmarkQuadtreeUpdate(b *testing.B)        { benchUpdate(b, newQuadtree) }
+func BenchmarkQuadtreeRaycast(b *testing.B)       { benchRaycast(b, newQuadtree) }
+func BenchmarkSweepAndPruneInsert(b *testing.B)   { benchInsert(b, newSweepAndPrune) }
+func BenchmarkSweepAndPruneRetrieve(b *testing.B) { benchRetrieve(b, newSweepAndPrune) }
+func BenchmarkSweepAndPruneUpdate(b *testing.B)   { benchUpdate(b, newSweepAndPrune) }
+func BenchmarkSweepAndPruneRaycast(b *testing.B)  { benchRaycast(b, newSweepAndPrune) }
+
+// benchLevel creates a floor with random hills, floating platforms and walls.
+func benchLevel() []*Rectangle {
+	rng := rand.New(rand.NewSource(benchSeed))
+
+	var tiles []*Rectangle
+	tile := func(x, y int) {
+		tiles = append(tiles, NewRectangle(
+			float32(x*benchTileSize), float32(y*benchTileSize), benchTileSize, benchTileSize,
+		))
+	}
+
+	ground := benchHeight - 4
+	for x := 0; x < benchWidth; x++ {
+		// Wander the ground up and down.
+		if rng.Intn(4) == 0 {
+			ground += rng.Intn(3) - 1
+		}
+		if ground < benchHeight/2 {
+			ground = benchHeight / 2
+		}
+		if ground > benchHeight-2 {
+			ground = benchHeight - 2
+		}
+
+		for y := ground; y < benchHeight; y++ {
+			tile(x, y)
+		}
+
+		// Sprinkle in some floating platforms.
+		if rng.Intn(6) == 0 {
+			tile(x, ground-4-rng.Intn(benchHeight/3))
+		}
+	}
+
+	return tiles
+}
+
+// newBenchMovers creates small rectangles scattered over the level.
+func newBenchMovers() []*Rectangle {
+	rng := rand.New(rand.NewSource(benchSeed))
+
+	mm := make([]*Rectangle, benchMovers)
+	for i := range mm {
+		mm[i] = NewRectangle(
+			rng.Float32()*benchWidth*benchTileSize,
+			rng.Float32()*benchHeight*benchTileSize,
+			12, 16,
+		)
+	}
+
+	return mm
+}
+
+// filledLevel returns a broadphase with every tile of the level in it.
+func filledLevel(newBroadphase func() Broadphase) Broadphase {
+	world := newBroadphase()
+	for _, t := range benchLevel() {
+		world.Insert(t)
+	}
+
+	return world
+}
+
+// reportQueries reports the average stats of running the query once for
+// every mover.
+func reportQueries(b *testing.B, world Broadphase, query func(q *Rectangle)) {
+	b.StopTimer()
+
+	var total QueryStats
+
+	queries := newBenchMovers()
+	for _, q := range queries {
+		query(q)
+
+		stats := world.Stats()
+		total.Nodes += stats.Nodes
+		total.Candidates += stats.Candidates
+		total.Results += stats.Results
+	}
+
+	n := float64(len(queries))
+	b.ReportMetric(float64(total.Nodes)/n, "nodes/query")
+	b.ReportMetric(float64(total.Candidates)/n, "candidates/query")
+	b.ReportMetric(float64(total.Results)/n, "results/query")
+}
+
+func benchInsert(b *testing.B, newBroadphase func() Broadphase) {
+	world := newBroadphase()
+	tiles := benchLevel()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		world.Clear()
+		for _, t := range tiles {
+			world.Insert(t)
+		}
+	}
+}
+
+func benchRetrieve(b *testing.B, newBroadphase func() Broadphase) {
+	world := filledLevel(newBroadphase)
+	queries := newBenchMovers()
+
+	retrieve := func(q *Rectangle) {
+		world.Retrieve(q.Rectangle)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		retrieve(queries[i%len(queries)])
+	}
+
+	reportQueries(b, world, retrieve)
+}
+
+func benchUpdate(b *testing.B, newBroadphase func() Broadphase) {
+	world := filledLevel(newBroadphase)
+
+	mm := newBenchMovers()
+	for _, m := range mm {
+		world.Insert(m)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		// Move every object a little bit like a normal update would.
+		for j, m := range mm {
+			dx := float32(1)
+			if (i/60+j)%2 == 0 {
+				dx = -1
+			}
+			m.Move(dx, 0)
+			world.Update(m)
+		}
+	}
+}
+
+func benchRaycast(b *testing.B, newBroadphase func() Broadphase) {
+	world := filledLevel(newBroadphase)
+	origins := newBenchMovers()
+
+	raycast := func(o *Rectangle) {
+		world.Raycast(o.Position(), r.NewVector2(1, 1), 256, nil)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		raycast(origins[i%len(origins)])
+	}
+
+	reportQueries(b, world, raycast)
+}
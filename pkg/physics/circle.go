@@ -0,0 +1,81 @@
+package physics
+
+import (
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var _ Shape = &Circle{}
+
+// Circle is a round shape made from a center point and a radius.
+type Circle struct {
+	*BasicShape
+	center r.Vector2
+	radius float32
+}
+
+// NewCircle returns a circle centered on the coordinates given.
+func NewCircle(x, y, radius float32) *Circle {
+	return &Circle{
+		BasicShape: NewBasicShape(),
+		center:     r.NewVector2(x, y),
+		radius:     radius,
+	}
+}
+
+// Radius returns the radius of the circle.
+func (c *Circle) Radius() float32 {
+	return c.radius
+}
+
+// SetRadius changes the size of the circle.
+func (c *Circle) SetRadius(radius float32) {
+	c.radius = radius
+}
+
+// Overlaps checks if the circle is overlapping a raylib rectangle.
+func (c *Circle) Overlaps(rec r.Rectangle) bool {
+	_, ok := collideHulls(hullOf(c), rectHull(rec))
+	return ok
+}
+
+// Position returns the top left corner of the box around the circle.
+func (c *Circle) Position() r.Vector2 {
+	return r.NewVector2(c.center.X-c.radius, c.center.Y-c.radius)
+}
+
+// MaxPosition returns the bottom right corner of the box around the circle.
+func (c *Circle) MaxPosition() r.Vector2 {
+	return r.NewVector2(c.center.X+c.radius, c.center.Y+c.radius)
+}
+
+// Center returns the center point of the circle.
+func (c *Circle) Center() r.Vector2 {
+	return c.center
+}
+
+// SetPosition moves the top left corner of the box around the circle to the
+// coordinates given, just like the rest of the shapes.
+func (c *Circle) SetPosition(x, y float32) {
+	c.center = r.NewVector2(x+c.radius, y+c.radius)
+}
+
+// Move moves the circle by the values specified.
+func (c *Circle) Move(x, y float32) {
+	c.center.X += x
+	c.center.Y += y
+}
+
+// Width returns the diameter of the circle.
+func (c *Circle) Width() float32 {
+	return c.radius * 2
+}
+
+// Height returns the diameter of the circle.
+func (c *Circle) Height() float32 {
+	return c.radius * 2
+}
+
+// Draw is used for debugging and draws the outline of the circle.
+func (c *Circle) Draw() {
+	r.DrawCircleLines(int(c.center.X), int(c.center.Y), c.radius, r.Orange)
+}
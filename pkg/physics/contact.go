@@ -0,0 +1,149 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/msg"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Phase is the point in a contact's lifetime that a message was sent at.
+type Phase int
+
+const (
+	// Enter is sent on the first update that two shapes touch.
+	Enter Phase = iota
+	// Stay is sent on every following update that the shapes are touching.
+	Stay
+	// Exit is sent on the first update that the shapes stopped touching.
+	Exit
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Enter:
+		return "enter"
+	case Stay:
+		return "stay"
+	case Exit:
+		return "exit"
+	}
+	return "unknown"
+}
+
+// Of returns the message type to listen to for this phase of the message
+// type given. For example Enter.Of(msg.Door) is sent when something walks into
+// a door's zone.
+func (p Phase) Of(msgType string) string {
+	return msgType + "." + p.String()
+}
+
+// contact is a shape that a body touched during an update.
+type contact struct {
+	shape   Shape
+	overlap r.Rectangle
+	normal  r.Vector2
+}
+
+// findContact returns the index of the shape in the contacts or -1.
+func findContact(contacts []contact, s Shape) int {
+	for i := range contacts {
+		if contacts[i].shape == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContactMessage is sent through a body's mailbox when it touches a solid.
+type ContactMessage struct {
+	Phase Phase
+	// Other is the shape that the body touched.
+	Other Shape
+	// Tags are the tags of the other shape.
+	Tags []common.Tag
+	// Overlap is how far the shapes were overlapping. Solids usually stop the
+	// body right on their surface, so this is often empty.
+	Overlap r.Rectangle
+	// Normal is the surface normal of the other shape facing the body.
+	Normal r.Vector2
+}
+
+// Type returns the contact message type for the phase of the message.
+func (c *ContactMessage) Type() string {
+	return c.Phase.Of(msg.Contact)
+}
+
+// touch records that the body touched the shape during this update.
+// Only the first touch of each shape is kept.
+func (b *Body) touch(s Shape, overlap r.Rectangle, normal r.Vector2) {
+	// Remember the walls and ceilings that the body ran into. Zones don't
+	// block the body, so they're never either.
+	if _, ok := s.(*Zone); !ok {
+		ax, ay := math.Abs(float64(normal.X)), math.Abs(float64(normal.Y))
+
+		switch {
+		case ax > ay:
+			// The normal points away from the wall.
+			b.wall = s
+			b.wallSide = common.Direction(-sign(normal.X))
+		case normal.Y > 0:
+			b.ceiling = s
+		}
+	}
+
+	if findContact(b.contacts, s) != -1 {
+		return
+	}
+
+	b.contacts = append(b.contacts, contact{s, overlap, normal})
+}
+
+// flushContacts compares the contacts from this update against the last one
+// and sends out the enter, stay and exit messages.
+func (b *Body) flushContacts() {
+	for _, c := range b.contacts {
+		phase := Enter
+		if findContact(b.lastContacts, c.shape) != -1 {
+			phase = Stay
+		}
+
+		b.dispatchContact(phase, c)
+	}
+
+	for _, c := range b.lastContacts {
+		if findContact(b.contacts, c.shape) == -1 {
+			b.dispatchContact(Exit, c)
+		}
+	}
+
+	// Reuse the old slice for the next update.
+	b.lastContacts, b.contacts = b.contacts, b.lastContacts[:0]
+}
+
+func (b *Body) dispatchContact(phase Phase, c contact) {
+	// Zones send messages to their own mailbox instead.
+	if z, ok := c.shape.(*Zone); ok {
+		z.dispatchMessage(phase, b.Space, c.overlap, c.normal)
+		return
+	}
+
+	b.mailbox.Dispatch(&ContactMessage{
+		Phase:   phase,
+		Other:   c.shape,
+		Tags:    c.shape.Tags(),
+		Overlap: c.overlap,
+		Normal:  c.normal,
+	})
+}
+
+// overlapNormal returns which side of the shape the collider is overlapping
+// from, using the axis that the two are overlapping the least on.
+func overlapNormal(shape, collider, overlap r.Rectangle) r.Vector2 {
+	if overlap.Width < overlap.Height {
+		return r.NewVector2(sign(collider.Center().X-shape.Center().X), 0)
+	}
+	return r.NewVector2(0, sign(collider.Center().Y-shape.Center().Y))
+}
@@ -0,0 +1,127 @@
+package physics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+)
+
+// Layer is a bitmask of collision layers. Every shape is on one or more
+// layers and has a mask of the layers it's allowed to collide with.
+type Layer uint32
+
+// Layers that are used by the game. Their names are set in the physics
+// section of settings.json in the same order, which LoadConfig checks.
+const (
+	LayerDefault Layer = 1 << iota
+	LayerPlayer
+	LayerEnemy
+	LayerPickup
+	LayerHurtbox
+	LayerHitbox
+	LayerProjectile
+
+	// LayerNone doesn't collide with anything.
+	LayerNone Layer = 0
+	// LayerAll collides with everything.
+	LayerAll Layer = ^Layer(0)
+)
+
+// layerNames are the names that settings.json has to give the layers above,
+// in the same order.
+var layerNames = []string{
+	"default", "player", "enemy", "pickup", "hurtbox", "hitbox", "projectile",
+}
+
+func init() {
+	common.RegisterConfigCheck(checkLayers)
+}
+
+// checkLayers makes sure that the layer names in settings.json are the ones
+// of the layer constants, so LayerNamed gives the same layers as the code.
+func checkLayers() error {
+	layers := common.Config.Physics.Layers
+
+	for i, name := range layerNames {
+		if i >= len(layers) || layers[i] != name {
+			return fmt.Errorf("physics.layers[%d] must be %q", i, name)
+		}
+	}
+
+	return nil
+}
+
+// maxLayers is the amount of layers that fit into the bitmask.
+const maxLayers = 32
+
+// Layered is anything that can be filtered by its collision layers.
+type Layered interface {
+	Layer() Layer
+	Mask() Layer
+}
+
+// Collides returns whether two layered objects are allowed to collide, which
+// is when both of their masks contain the other's layer.
+func Collides(a, b Layered) bool {
+	return a.Mask()&b.Layer() != 0 && b.Mask()&a.Layer() != 0
+}
+
+// LayerNamed returns the layer with the name given in settings.json.
+// Multiple names can be given to combine the layers into a mask.
+func LayerNamed(names ...string) (Layer, error) {
+	var layer Layer
+
+	for _, name := range names {
+		idx := layerIndex(name)
+		if idx == -1 {
+			return LayerNone, fmt.Errorf("unknown layer: %q", name)
+		}
+		if idx >= maxLayers {
+			return LayerNone, fmt.Errorf("layer %q is past the max of %d layers", name, maxLayers)
+		}
+
+		layer |= 1 << uint(idx)
+	}
+
+	return layer, nil
+}
+
+func layerIndex(name string) int {
+	if common.Config == nil {
+		return -1
+	}
+
+	for i, n := range common.Config.Physics.Layers {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// String returns the names of the layers in the mask separated by pipes.
+func (l Layer) String() string {
+	switch l {
+	case LayerNone:
+		return "none"
+	case LayerAll:
+		return "all"
+	}
+
+	var names []string
+	for i := uint(0); i < maxLayers; i++ {
+		if l&(1<<i) == 0 {
+			continue
+		}
+
+		if common.Config != nil && int(i) < len(common.Config.Physics.Layers) {
+			names = append(names, common.Config.Physics.Layers[i])
+		} else {
+			names = append(names, fmt.Sprintf("layer%d", i))
+		}
+	}
+
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,82 @@
+package physics
+
+import (
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+)
+
+// bounceThreshold is the slowest speed that a body has to hit a bouncy
+// surface at to bounce off of it. Without it bodies resting on bouncy
+// surfaces would jitter from gravity pulling them in every update.
+const bounceThreshold = 60
+
+// Material describes how a surface feels to the bodies touching it.
+type Material struct {
+	// Name is the name of the material in settings.json.
+	Name string
+	// Friction is how quickly bodies on the surface slow down on the X axis
+	// in units per second.
+	Friction float32
+	// Restitution is how much of a body's speed is kept when it bounces off
+	// the surface, where 0 doesn't bounce at all and 1 bounces forever.
+	Restitution float32
+	// Acceleration multiplies how quickly bodies on the surface can speed up.
+	Acceleration float32
+	// Surface is the kind of surface, such as "stone" or "ice", which can be
+	// used for things like footstep sounds.
+	Surface string
+}
+
+// defaultMaterial is loaded from the config the first time it's needed.
+var defaultMaterial *Material
+
+// DefaultMaterial returns the "default" material from settings.json, which
+// shapes without a material of their own use.
+func DefaultMaterial() *Material {
+	if defaultMaterial != nil {
+		return defaultMaterial
+	}
+
+	m, err := MaterialNamed("default")
+	if err != nil {
+		// The config hasn't been loaded, so don't remember this one.
+		return &Material{Name: "default", Acceleration: 1}
+	}
+
+	defaultMaterial = m
+
+	return m
+}
+
+// materials keeps every material that was loaded from the config, so shapes
+// using the same material share it.
+var materials = make(map[string]*Material)
+
+// MaterialNamed returns the material with the name given in settings.json.
+func MaterialNamed(name string) (*Material, error) {
+	if m, ok := materials[name]; ok {
+		return m, nil
+	}
+
+	if common.Config == nil {
+		return nil, fmt.Errorf("unknown material: %q", name)
+	}
+
+	cfg, ok := common.Config.Physics.Materials[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown material: %q", name)
+	}
+
+	m := &Material{
+		Name:         name,
+		Friction:     cfg.Friction,
+		Restitution:  cfg.Restitution,
+		Acceleration: cfg.Acceleration,
+		Surface:      cfg.Surface,
+	}
+
+	materials[name] = m
+
+	return m, nil
+}
@@ -0,0 +1,265 @@
+package physics
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/ease"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ Shape        = &MovingPlatform{}
+	_ SpatialAdder = &MovingPlatform{}
+	_ Kinematic    = &MovingPlatform{}
+)
+
+// Kinematic is a shape that moves by itself every update instead of being
+// pushed around by physics. Bodies standing on it are carried along with it.
+type Kinematic interface {
+	Update(dt float32)
+	// Delta returns how far the shape moved in the last update.
+	Delta() r.Vector2
+}
+
+// PathMode tells a moving platform what to do once it reaches the last
+// waypoint of its path.
+type PathMode int
+
+const (
+	// PathLoop goes from the last waypoint straight back to the first.
+	PathLoop PathMode = iota
+	// PathPingPong turns around and follows the waypoints in reverse.
+	PathPingPong
+)
+
+// PathFunc returns the position that a platform should be at after the amount
+// of seconds given.
+type PathFunc func(elapsed float32) r.Vector2
+
+// MovingPlatform is a solid rectangle that moves along a path of waypoints or
+// a path function. It updates its own cells in the spatial hashmap and pushes
+// bodies out of its way while carrying anything standing on top of it.
+type MovingPlatform struct {
+	waypoints []r.Vector2
+	speed     float32
+	mode      PathMode
+	ease      ease.Func
+
+	// from and to are the waypoint indexes that the platform is between and
+	// progress is how far it's gotten from one to the other.
+	from, to  int
+	direction int
+	progress  float32
+
+	path    PathFunc
+	elapsed float32
+
+	delta    r.Vector2
+	previous r.Vector2
+	world    Broadphase
+
+	*Rectangle
+}
+
+// NewMovingPlatform creates a platform that travels between the waypoints at
+// the speed given in units per second. The platform starts on the first
+// waypoint and waypoints are the platform's top left position.
+func NewMovingPlatform(w, h float32, speed float32, waypoints ...r.Vector2) *MovingPlatform {
+	p := &MovingPlatform{
+		waypoints: waypoints,
+		speed:     speed,
+		ease:      ease.Linear,
+		direction: 1,
+		Rectangle: NewRectangle(0, 0, w, h),
+	}
+
+	if len(waypoints) > 0 {
+		p.SetPosition(waypoints[0].X, waypoints[0].Y)
+	}
+	if len(waypoints) > 1 {
+		p.to = 1
+	}
+
+	p.previous = p.Position()
+
+	return p
+}
+
+// NewPathPlatform creates a platform that is moved by the path function.
+func NewPathPlatform(w, h float32, path PathFunc) *MovingPlatform {
+	p := &MovingPlatform{
+		path:      path,
+		ease:      ease.Linear,
+		direction: 1,
+		Rectangle: NewRectangle(0, 0, w, h),
+	}
+
+	start := path(0)
+	p.SetPosition(start.X, start.Y)
+	p.previous = p.Position()
+
+	return p
+}
+
+// SetMode changes what the platform does at the end of its waypoints.
+func (p *MovingPlatform) SetMode(mode PathMode) {
+	p.mode = mode
+}
+
+// SetEase changes how the platform speeds up and slows down between each
+// waypoint. This isn't used for path functions.
+func (p *MovingPlatform) SetEase(fn ease.Func) {
+	p.ease = fn
+}
+
+// Add inserts the platform into the broadphase and remembers it so the
+// platform can update itself whenever it moves.
+func (p *MovingPlatform) Add(w Broadphase) {
+	p.world = w
+	w.Insert(p)
+}
+
+// Delta returns how far the platform moved in the last update.
+func (p *MovingPlatform) Delta() r.Vector2 {
+	return p.delta
+}
+
+// Interpolate returns the position of the platform between its previous and
+// current update.
+func (p *MovingPlatform) Interpolate(alpha float32) r.Vector2 {
+	return p.previous.Lerp(p.Position(), alpha)
+}
+
+// Update moves the platform along its path.
+func (p *MovingPlatform) Update(dt float32) {
+	p.previous = p.Position()
+
+	var target r.Vector2
+	if p.path != nil {
+		p.elapsed += dt
+		target = p.path(p.elapsed)
+	} else {
+		target = p.advance(dt)
+	}
+
+	p.delta = target.Subtract(p.previous)
+	if p.delta.X == 0 && p.delta.Y == 0 {
+		return
+	}
+
+	p.SetPosition(target.X, target.Y)
+
+	if p.world != nil {
+		p.world.Update(p)
+		p.push()
+	}
+}
+
+// advance moves the progress along the waypoints and returns the new position.
+func (p *MovingPlatform) advance(dt float32) r.Vector2 {
+	if len(p.waypoints) < 2 {
+		return p.Position()
+	}
+
+	remaining := p.speed * dt
+	// The amount of steps is capped in case every waypoint is in the same spot.
+	for steps := 0; remaining > 0 && steps <= 2*len(p.waypoints); steps++ {
+		length := p.waypoints[p.from].Distance(p.waypoints[p.to])
+		if length == 0 {
+			p.next()
+			continue
+		}
+
+		left := (1 - p.progress) * length
+		if remaining < left {
+			p.progress += remaining / length
+			break
+		}
+
+		// Reached the waypoint, so carry on towards the next one.
+		remaining -= left
+		p.next()
+	}
+
+	return p.waypoints[p.from].Lerp(p.waypoints[p.to], p.ease(p.progress))
+}
+
+// next starts heading towards the next waypoint.
+func (p *MovingPlatform) next() {
+	p.progress = 0
+	p.from = p.to
+
+	switch p.mode {
+	case PathPingPong:
+		if p.to+p.direction < 0 || p.to+p.direction >= len(p.waypoints) {
+			p.direction = -p.direction
+		}
+		p.to += p.direction
+	default:
+		p.to = (p.to + 1) % len(p.waypoints)
+	}
+}
+
+// push moves any bodies that the platform has moved into out of the way.
+// Anything else in their way stops them, just like when they move by
+// themselves. Bodies that are riding the platform are skipped since they
+// carry themselves.
+func (p *MovingPlatform) push() {
+	for _, obj := range p.world.Retrieve(p) {
+		body, ok := bodyOf(obj)
+		if !ok || body.ground == p || !Collides(p, body) {
+			continue
+		}
+
+		// Push the body in whichever direction the platform is mostly moving
+		// in, far enough to get every collider out of the platform.
+		var delta r.Vector2
+		for _, shape := range *body.Space {
+			collider, ok := shape.(*Rectangle)
+			if !ok || !p.Overlaps(collider.Rectangle) {
+				continue
+			}
+
+			overlap := p.Rectangle.GetOverlapRec(collider.Rectangle)
+
+			if abs(p.delta.X) >= abs(p.delta.Y) {
+				if overlap.Width > abs(delta.X) {
+					delta.X = sign(p.delta.X) * overlap.Width
+				}
+			} else if overlap.Height > abs(delta.Y) {
+				delta.Y = sign(p.delta.Y) * overlap.Height
+			}
+		}
+
+		body.moveAlong(delta, p)
+		p.world.Update(body.handle)
+	}
+}
+
+// bodyOf returns the body of an object that's in a broadphase.
+func bodyOf(obj interface{}) (*Body, bool) {
+	switch t := obj.(type) {
+	case *Actor:
+		return t.Rigidbody, true
+	case *Body:
+		return t, true
+	}
+
+	return nil, false
+}
+
+// Draw is used for debugging and draws the outline of the platform.
+func (p *MovingPlatform) Draw() {
+	pos := p.Interpolate(common.Alpha)
+
+	r.DrawRectangleLinesEx(
+		r.NewRectangle(pos.X, pos.Y, p.Width(), p.Height()), 1, r.Purple,
+	)
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
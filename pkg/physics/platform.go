@@ -1,14 +1,30 @@
 package physics
 
-import r "github.com/lachee/raylib-goplus/raylib"
+var _ Shape = &Platform{}
 
-type Platform Rectangle
+// Platform is a rectangle that can only be collided with from above. Bodies
+// may jump through it from below and land on top of it.
+type Platform struct {
+	*Rectangle
+}
 
+// NewPlatform creates a one-way platform.
 func NewPlatform(x, y, w, h float32) *Platform {
 	p := &Platform{
-		BasicShape: &BasicShape{},
-		Rectangle:  r.NewRectangle(x, y, w, h),
+		Rectangle: NewRectangle(x, y, w, h),
 	}
 
 	return p
 }
+
+// isOneWay returns if bodies are allowed to drop through the shape.
+func isOneWay(s Shape) bool {
+	switch t := s.(type) {
+	case *Platform:
+		return true
+	case *SlopePlatform:
+		return t.OneWay()
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package physics
+
+import (
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var _ Shape = &ConvexPolygon{}
+
+// ConvexPolygon is a shape made from any amount of points where none of the
+// corners point inwards. The points can be in either winding order.
+type ConvexPolygon struct {
+	*BasicShape
+	points []r.Vector2
+}
+
+// NewConvexPolygon returns a polygon from the points given. The points have to
+// make a convex shape, otherwise collisions won't be correct.
+func NewConvexPolygon(points ...r.Vector2) *ConvexPolygon {
+	pp := make([]r.Vector2, len(points))
+	copy(pp, points)
+
+	return &ConvexPolygon{
+		BasicShape: NewBasicShape(),
+		points:     pp,
+	}
+}
+
+// Points returns the corners of the polygon.
+func (p *ConvexPolygon) Points() []r.Vector2 {
+	return p.points
+}
+
+// Overlaps checks if the polygon is overlapping a raylib rectangle.
+func (p *ConvexPolygon) Overlaps(rec r.Rectangle) bool {
+	_, ok := collideHulls(hullOf(p), rectHull(rec))
+	return ok
+}
+
+// Position returns the top left corner of the box around the polygon.
+func (p *ConvexPolygon) Position() r.Vector2 {
+	min, _ := pointBounds(p.points)
+	return min
+}
+
+// MaxPosition returns the bottom right corner of the box around the polygon.
+func (p *ConvexPolygon) MaxPosition() r.Vector2 {
+	_, max := pointBounds(p.points)
+	return max
+}
+
+// Center returns the average of all the points of the polygon.
+func (p *ConvexPolygon) Center() r.Vector2 {
+	var c r.Vector2
+	for _, pt := range p.points {
+		c = c.Add(pt)
+	}
+
+	if len(p.points) > 0 {
+		c = c.Scale(1 / float32(len(p.points)))
+	}
+
+	return c
+}
+
+// SetPosition moves the top left corner of the box around the polygon to the
+// coordinates given.
+func (p *ConvexPolygon) SetPosition(x, y float32) {
+	min := p.Position()
+	p.Move(x-min.X, y-min.Y)
+}
+
+// Move moves every point of the polygon by the values specified.
+func (p *ConvexPolygon) Move(x, y float32) {
+	for i := range p.points {
+		p.points[i].X += x
+		p.points[i].Y += y
+	}
+}
+
+// Width returns the width of the box around the polygon.
+func (p *ConvexPolygon) Width() float32 {
+	min, max := pointBounds(p.points)
+	return max.X - min.X
+}
+
+// Height returns the height of the box around the polygon.
+func (p *ConvexPolygon) Height() float32 {
+	min, max := pointBounds(p.points)
+	return max.Y - min.Y
+}
+
+// Draw is used for debugging and draws the outline of the polygon.
+func (p *ConvexPolygon) Draw() {
+	for i := range p.points {
+		r.DrawLineV(p.points[i], p.points[(i+1)%len(p.points)], r.Orange)
+	}
+}
+
+// pointBounds returns the smallest and largest coordinates of the points.
+func pointBounds(points []r.Vector2) (r.Vector2, r.Vector2) {
+	if len(points) == 0 {
+		return r.Vector2{}, r.Vector2{}
+	}
+
+	min, max := points[0], points[0]
+	for _, pt := range points[1:] {
+		if pt.X < min.X {
+			min.X = pt.X
+		}
+		if pt.Y < min.Y {
+			min.Y = pt.Y
+		}
+		if pt.X > max.X {
+			max.X = pt.X
+		}
+		if pt.Y > max.Y {
+			max.Y = pt.Y
+		}
+	}
+
+	return min, max
+}
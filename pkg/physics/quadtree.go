@@ -0,0 +1,192 @@
+package physics
+
+import (
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+const (
+	// quadMaxObjects is how many objects a node holds before it splits.
+	quadMaxObjects = 8
+	// quadMaxDepth is how deep the tree can go before nodes stop splitting.
+	quadMaxDepth = 8
+)
+
+// Quadtree is a broadphase that splits the world into quarters whenever an
+// area gets crowded. It's a good fit for big levels where objects are spread
+// out unevenly. Objects that are outside of the tree's bounds are kept in the
+// root node, so the bounds should cover the whole level.
+type Quadtree struct {
+	root *quadNode
+
+	proxySet
+}
+
+type quadNode struct {
+	bounds   r.Rectangle
+	depth    int
+	proxies  []*proxy
+	children []*quadNode
+}
+
+// NewQuadtree returns an empty quadtree covering the bounds given.
+func NewQuadtree(bounds r.Rectangle) *Quadtree {
+	return &Quadtree{
+		root: &quadNode{bounds: bounds},
+	}
+}
+
+// Clear empties the quadtree.
+func (q *Quadtree) Clear() {
+	q.root = &quadNode{bounds: q.root.bounds}
+	q.reset()
+}
+
+// InsertI allows from interfaces to be placed in.
+func (q *Quadtree) InsertI(objects ...interface{}) error {
+	return insertObjects(q, objects...)
+}
+
+// Insert adds the transformers into the tree.
+func (q *Quadtree) Insert(t ...Transformer) {
+	for i := range t {
+		if p := q.add(t[i]); p != nil {
+			q.root.insert(p)
+		}
+	}
+}
+
+// Remove takes the transformer out of the tree.
+func (q *Quadtree) Remove(t Transformer) {
+	if p := q.remove(t); p != nil {
+		p.node.detach(p)
+	}
+}
+
+// Update moves the transformer to the node that it belongs in now. It's only
+// moved if it left its node or can now fit into one of the node's children.
+func (q *Quadtree) Update(t Transformer) {
+	p, ok := q.proxies[t]
+	if !ok {
+		return
+	}
+
+	p.bounds = boundsOf(t)
+
+	node := p.node
+	fits := node == q.root || contains(node.bounds, p.bounds)
+	if fits && node.childFor(p.bounds) == nil {
+		return
+	}
+
+	node.detach(p)
+	q.root.insert(p)
+}
+
+// Retrieve returns the objects touching the transformer's bounds.
+func (q *Quadtree) Retrieve(t Transformer) []interface{} {
+	return q.RetrieveLayers(t, queryMask(t))
+}
+
+// RetrieveLayers returns the objects touching the transformer's bounds that
+// are on any of the layers in the mask.
+func (q *Quadtree) RetrieveLayers(t Transformer, mask Layer) []interface{} {
+	if t == nil {
+		return q.all(mask)
+	}
+
+	q.begin()
+
+	return q.query(nil, q.root, boundsOf(t), mask)
+}
+
+func (q *Quadtree) query(res []interface{}, node *quadNode, bounds r.Rectangle, mask Layer) []interface{} {
+	q.stats.Nodes++
+
+	for _, p := range node.proxies {
+		res = q.visit(res, p, bounds, mask)
+	}
+
+	for _, child := range node.children {
+		if touching(child.bounds, bounds) {
+			res = q.query(res, child, bounds, mask)
+		}
+	}
+
+	return res
+}
+
+// Raycast returns the nearest shape that the ray hits.
+func (q *Quadtree) Raycast(origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool) {
+	return raycast(q, origin, dir, maxDist, filter)
+}
+
+// ShapeCast returns the nearest shape that the rectangle would hit.
+func (q *Quadtree) ShapeCast(rec r.Rectangle, delta r.Vector2, filter QueryFilter) (RayHit, bool) {
+	return shapeCast(q, rec, delta, filter)
+}
+
+// insert pushes the proxy down into the smallest node that fits it.
+func (n *quadNode) insert(p *proxy) {
+	for {
+		child := n.childFor(p.bounds)
+		if child == nil {
+			break
+		}
+		n = child
+	}
+
+	p.node = n
+	n.proxies = append(n.proxies, p)
+
+	if n.children == nil && len(n.proxies) > quadMaxObjects && n.depth < quadMaxDepth {
+		n.split()
+	}
+}
+
+// detach removes the proxy from the node.
+func (n *quadNode) detach(p *proxy) {
+	for i := range n.proxies {
+		if n.proxies[i] == p {
+			n.proxies = append(n.proxies[:i], n.proxies[i+1:]...)
+			break
+		}
+	}
+
+	p.node = nil
+}
+
+// childFor returns the child that completely contains the bounds, or nil.
+func (n *quadNode) childFor(bounds r.Rectangle) *quadNode {
+	for _, child := range n.children {
+		if contains(child.bounds, bounds) {
+			return child
+		}
+	}
+	return nil
+}
+
+// split creates the four children of the node and moves down every proxy
+// that fits into one of them.
+func (n *quadNode) split() {
+	w, h := n.bounds.Width/2, n.bounds.Height/2
+	x, y := n.bounds.X, n.bounds.Y
+
+	n.children = []*quadNode{
+		{bounds: r.NewRectangle(x, y, w, h), depth: n.depth + 1},
+		{bounds: r.NewRectangle(x+w, y, w, h), depth: n.depth + 1},
+		{bounds: r.NewRectangle(x, y+h, w, h), depth: n.depth + 1},
+		{bounds: r.NewRectangle(x+w, y+h, w, h), depth: n.depth + 1},
+	}
+
+	proxies := n.proxies
+	n.proxies = nil
+
+	for _, p := range proxies {
+		if child := n.childFor(p.bounds); child != nil {
+			child.insert(p)
+		} else {
+			p.node = n
+			n.proxies = append(n.proxies, p)
+		}
+	}
+}
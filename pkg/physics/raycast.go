@@ -0,0 +1,408 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// RayHit is the closest shape found by a Raycast or ShapeCast.
+type RayHit struct {
+	// Point is where the ray hit the shape. For shape casts this is the
+	// position of the cast rectangle at the moment of impact.
+	Point r.Vector2
+	// Normal is the surface normal of the shape that was hit.
+	Normal r.Vector2
+	// Distance is how far the ray or rectangle travelled before the hit.
+	Distance float32
+	// Shape is the shape that was hit.
+	Shape Shape
+}
+
+// QueryFilter decides which shapes a query is allowed to hit.
+// Returning false makes the query ignore the shape.
+type QueryFilter func(Shape) bool
+
+// FilterTags only allows shapes that have all the tags provided.
+func FilterTags(tags ...common.Tag) QueryFilter {
+	return func(s Shape) bool {
+		return s.HasTags(tags...)
+	}
+}
+
+// FilterOutTags ignores any shapes that have all the tags provided.
+func FilterOutTags(tags ...common.Tag) QueryFilter {
+	return func(s Shape) bool {
+		return !s.HasTags(tags...)
+	}
+}
+
+// FilterLayers only allows shapes that are on any of the layers in the mask.
+func FilterLayers(mask Layer) QueryFilter {
+	return func(s Shape) bool {
+		return s.Layer()&mask != 0
+	}
+}
+
+// Raycast shoots a ray from the origin towards dir and returns the nearest
+// shape hit within maxDist. The ray walks through the hashmap cell by cell so
+// only shapes close to the ray are ever checked, and it stops once it has
+// left every occupied cell behind.
+//
+// Zones are never hit since they don't block anything, and platforms can only
+// be hit from above.
+func (s *SpatialHashmap) Raycast(origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool) {
+	if !validRay(dir, maxDist) {
+		return RayHit{}, false
+	}
+	dir = dir.Normalize()
+
+	minCell, maxCell, ok := s.occupiedCells()
+	if !ok {
+		return RayHit{}, false
+	}
+
+	var (
+		best  RayHit
+		found bool
+	)
+
+	best.Distance = maxDist
+	s.begin()
+
+	check := func(proxies []*proxy) {
+		s.stats.Nodes++
+
+		for _, p := range proxies {
+			s.stats.Candidates++
+			if p.stamp == s.stamp {
+				s.stats.Duplicates++
+				continue
+			}
+			p.stamp = s.stamp
+
+			if hit, ok := raycastObject(p.object, origin, dir, best.Distance, filter); ok {
+				best = hit
+				found = true
+			}
+		}
+	}
+
+	size := float32(int(1) << uint(s.cellSize))
+	cx, cy := cell(origin.X, s.cellSize), cell(origin.Y, s.cellSize)
+	stepX, stepY := int(sign(dir.X)), int(sign(dir.Y))
+
+	// tMax is the distance along the ray to the next cell border on each
+	// axis and tDelta is the distance it takes to cross a whole cell.
+	inf := float32(math.Inf(1))
+	tMaxX, tMaxY := inf, inf
+	tDeltaX, tDeltaY := inf, inf
+
+	if stepX != 0 {
+		border := float32(cx) * size
+		if stepX > 0 {
+			border += size
+		}
+		tMaxX = (border - origin.X) / dir.X
+		tDeltaX = size / float32(math.Abs(float64(dir.X)))
+	}
+	if stepY != 0 {
+		border := float32(cy) * size
+		if stepY > 0 {
+			border += size
+		}
+		tMaxY = (border - origin.Y) / dir.Y
+		tDeltaY = size / float32(math.Abs(float64(dir.Y)))
+	}
+
+	var t float32
+	for t <= best.Distance {
+		check(s.hash[point{cx, cy}])
+
+		// Step into whichever cell border is closest.
+		if tMaxX < tMaxY {
+			t = tMaxX
+			tMaxX += tDeltaX
+			cx += stepX
+		} else {
+			t = tMaxY
+			tMaxY += tDeltaY
+			cy += stepY
+		}
+
+		// Nothing is left to hit once the ray is past the occupied cells.
+		if (stepX >= 0 && cx > maxCell.X()) || (stepX <= 0 && cx < minCell.X()) ||
+			(stepY >= 0 && cy > maxCell.Y()) || (stepY <= 0 && cy < minCell.Y()) {
+			break
+		}
+	}
+
+	if found {
+		s.stats.Results = 1
+	}
+
+	return best, found
+}
+
+// validRay returns if a ray can be cast, which needs a direction and a
+// distance that's finite and not negative.
+func validRay(dir r.Vector2, maxDist float32) bool {
+	if dir.X == 0 && dir.Y == 0 {
+		return false
+	}
+
+	d := float64(maxDist)
+	return d >= 0 && !math.IsInf(d, 0) && !math.IsNaN(d)
+}
+
+// ShapeCast sweeps a rectangle by delta through the hashmap and returns the
+// nearest shape that it would hit. It uses the same rules as Raycast.
+func (s *SpatialHashmap) ShapeCast(rec r.Rectangle, delta r.Vector2, filter QueryFilter) (RayHit, bool) {
+	return shapeCast(s, rec, delta, filter)
+}
+
+// raycastObject checks a ray against anything that may be in the hashmap.
+func raycastObject(obj interface{}, origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool) {
+	var (
+		best  RayHit
+		found bool
+	)
+
+	// nested checks all the shapes within a space and keeps the closest.
+	nested := func(space *Space) {
+		for _, shape := range *space {
+			if hit, ok := raycastObject(shape, origin, dir, maxDist, filter); ok {
+				best = hit
+				found = true
+				maxDist = hit.Distance
+			}
+		}
+	}
+
+	switch t := obj.(type) {
+	case *Actor:
+		nested(t.Space)
+	case *SlopePlatform:
+		nested(t.Space)
+	case *Space:
+		nested(t)
+	case *Zone:
+		// Zones don't stop anything.
+	case *Platform:
+		// Platforms can only be hit from above.
+		if dir.Y <= 0 || !allowed(t, filter) {
+			break
+		}
+		dist, normal, ok := raycastRectangle(t.Rectangle.Rectangle, origin, dir, maxDist)
+		if ok && normal.Y < 0 {
+			best = RayHit{origin.Add(dir.Scale(dist)), normal, dist, t}
+			found = true
+		}
+	case *Rectangle:
+		if !allowed(t, filter) {
+			break
+		}
+		if dist, normal, ok := raycastRectangle(t.Rectangle, origin, dir, maxDist); ok {
+			best = RayHit{origin.Add(dir.Scale(dist)), normal, dist, t}
+			found = true
+		}
+	case *MovingPlatform:
+		if !allowed(t, filter) {
+			break
+		}
+		if dist, normal, ok := raycastRectangle(t.Rectangle.Rectangle, origin, dir, maxDist); ok {
+			best = RayHit{origin.Add(dir.Scale(dist)), normal, dist, t}
+			found = true
+		}
+	case *Circle:
+		if !allowed(t, filter) {
+			break
+		}
+		if dist, normal, ok := raycastCircle(t.center, t.radius, origin, dir, maxDist); ok {
+			best = RayHit{origin.Add(dir.Scale(dist)), normal, dist, t}
+			found = true
+		}
+	case *ConvexPolygon:
+		if !allowed(t, filter) {
+			break
+		}
+		// The closest edge that the ray crosses is where it enters.
+		for i := range t.points {
+			p1, p2 := t.points[i], t.points[(i+1)%len(t.points)]
+			if dist, normal, ok := raycastSegment(p1, p2, origin, dir, maxDist); ok {
+				best = RayHit{origin.Add(dir.Scale(dist)), normal, dist, t}
+				found = true
+				maxDist = dist
+			}
+		}
+	case *Slope:
+		if !allowed(t, filter) {
+			break
+		}
+		if dist, normal, ok := raycastSegment(t.p1, t.p2, origin, dir, maxDist); ok {
+			best = RayHit{origin.Add(dir.Scale(dist)), normal, dist, t}
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// castHit is a sweep hit that also remembers which shape was hit.
+type castHit struct {
+	Hit
+	shape Shape
+}
+
+// sweepObject sweeps a rectangle against anything that may be in the hashmap.
+func sweepObject(obj interface{}, rec r.Rectangle, delta r.Vector2, filter QueryFilter) (castHit, bool) {
+	var (
+		best  castHit
+		found bool
+	)
+
+	nested := func(space *Space) {
+		for _, shape := range *space {
+			if hit, ok := sweepObject(shape, rec, delta, filter); ok && (!found || hit.Time < best.Time) {
+				best = hit
+				found = true
+			}
+		}
+	}
+
+	switch t := obj.(type) {
+	case *Actor:
+		nested(t.Space)
+	case *SlopePlatform:
+		nested(t.Space)
+	case *Space:
+		nested(t)
+	case *Zone:
+		// Zones don't stop anything.
+	case Shape:
+		sweeper, ok := t.(Sweeper)
+		if !ok || !allowed(t, filter) {
+			break
+		}
+		if hit, ok := sweeper.Sweep(rec, delta); ok {
+			best = castHit{hit, t}
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// raycastRectangle uses the slab method to find where a ray enters a rectangle.
+// Rays starting inside of the rectangle don't count as a hit.
+func raycastRectangle(rec r.Rectangle, origin, dir r.Vector2, maxDist float32) (float32, r.Vector2, bool) {
+	entryX, exitX, ok := raySlab(origin.X, dir.X, rec.X, rec.Width)
+	if !ok {
+		return 0, r.Vector2{}, false
+	}
+	entryY, exitY, ok := raySlab(origin.Y, dir.Y, rec.Y, rec.Height)
+	if !ok {
+		return 0, r.Vector2{}, false
+	}
+
+	entry := float32(math.Max(float64(entryX), float64(entryY)))
+	exit := float32(math.Min(float64(exitX), float64(exitY)))
+
+	if entry > exit || entry < 0 || entry > maxDist {
+		return 0, r.Vector2{}, false
+	}
+
+	var normal r.Vector2
+	if entryX > entryY {
+		normal.X = -sign(dir.X)
+	} else {
+		normal.Y = -sign(dir.Y)
+	}
+
+	return entry, normal, true
+}
+
+// raySlab returns the distances along a ray where it enters and exits a span.
+func raySlab(origin, dir, pos, size float32) (float32, float32, bool) {
+	inf := float32(math.Inf(1))
+
+	if dir == 0 {
+		// A ray parallel to the span has to start within it.
+		if origin < pos || origin > pos+size {
+			return 0, 0, false
+		}
+		return -inf, inf, true
+	}
+
+	entry := (pos - origin) / dir
+	exit := (pos + size - origin) / dir
+	if entry > exit {
+		entry, exit = exit, entry
+	}
+
+	return entry, exit, true
+}
+
+// raycastSegment finds where a ray crosses the line segment from p1 to p2.
+func raycastSegment(p1, p2, origin, dir r.Vector2, maxDist float32) (float32, r.Vector2, bool) {
+	edge := p2.Subtract(p1)
+
+	denom := cross(dir, edge)
+	// Parallel lines never cross.
+	if denom == 0 {
+		return 0, r.Vector2{}, false
+	}
+
+	diff := p1.Subtract(origin)
+	dist := cross(diff, edge) / denom
+	along := cross(diff, dir) / denom
+
+	if dist < 0 || dist > maxDist || along < 0 || along > 1 {
+		return 0, r.Vector2{}, false
+	}
+
+	// Make the normal face against the ray.
+	normal := r.NewVector2(edge.Y, -edge.X).Normalize()
+	if normal.DotProduct(dir) > 0 {
+		normal = normal.Negate()
+	}
+
+	return dist, normal, true
+}
+
+// raycastCircle finds where a ray enters a circle.
+// Rays starting inside of the circle don't count as a hit.
+func raycastCircle(center r.Vector2, radius float32, origin, dir r.Vector2, maxDist float32) (float32, r.Vector2, bool) {
+	diff := origin.Subtract(center)
+
+	// Solve for the distance where the ray is exactly a radius away.
+	b := diff.DotProduct(dir)
+	c := diff.DotProduct(diff) - radius*radius
+	if c < 0 {
+		return 0, r.Vector2{}, false
+	}
+
+	disc := b*b - c
+	if disc < 0 {
+		return 0, r.Vector2{}, false
+	}
+
+	dist := -b - float32(math.Sqrt(float64(disc)))
+	if dist < 0 || dist > maxDist {
+		return 0, r.Vector2{}, false
+	}
+
+	normal := origin.Add(dir.Scale(dist)).Subtract(center).Normalize()
+
+	return dist, normal, true
+}
+
+func cross(a, b r.Vector2) float32 {
+	return a.X*b.Y - a.Y*b.X
+}
+
+func allowed(s Shape, filter QueryFilter) bool {
+	return filter == nil || filter(s)
+}
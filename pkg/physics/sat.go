@@ -0,0 +1,209 @@
+package physics
+
+import (
+	"math"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// hull is a convex shape made from its corners and a radius that rounds it
+// out. Circles are a single point with a radius and slopes are two points.
+type hull struct {
+	points []r.Vector2
+	radius float32
+}
+
+// rectHull returns the four corners of a raylib rectangle.
+func rectHull(rec r.Rectangle) hull {
+	return hull{points: []r.Vector2{
+		r.NewVector2(rec.X, rec.Y),
+		r.NewVector2(rec.X+rec.Width, rec.Y),
+		r.NewVector2(rec.X+rec.Width, rec.Y+rec.Height),
+		r.NewVector2(rec.X, rec.Y+rec.Height),
+	}}
+}
+
+// hullOf returns the hull of a single shape. Shapes that are made of other
+// shapes, like spaces, don't have a hull of their own.
+func hullOf(s Shape) hull {
+	switch t := s.(type) {
+	case *Circle:
+		return hull{points: []r.Vector2{t.center}, radius: t.radius}
+	case *ConvexPolygon:
+		return hull{points: t.points}
+	case *Slope:
+		return hull{points: []r.Vector2{t.p1, t.p2}}
+	case *Rectangle:
+		return rectHull(t.Rectangle)
+	case *Platform:
+		return rectHull(t.Rectangle.Rectangle)
+	case *MovingPlatform:
+		return rectHull(t.Rectangle.Rectangle)
+	case *Zone:
+		return rectHull(t.Rectangle.Rectangle)
+	}
+
+	return hull{}
+}
+
+// children returns the shapes inside of shapes that are made of other shapes.
+func children(s Shape) (*Space, bool) {
+	switch t := s.(type) {
+	case *Space:
+		return t, true
+	case *Body:
+		return t.Space, true
+	case *SlopePlatform:
+		return t.Space, true
+	}
+
+	return nil, false
+}
+
+// Collide checks if two shapes overlap using the separating axis theorem and
+// returns the minimum translation vector, which is the shortest move that
+// pushes a out of b. Shapes that are only touching don't count.
+//
+// Spaces are checked shape by shape and the deepest overlap is returned.
+func Collide(a, b Shape) (r.Vector2, bool) {
+	if space, ok := children(a); ok {
+		return collideSpace(space, func(s Shape) (r.Vector2, bool) {
+			return Collide(s, b)
+		})
+	}
+	if space, ok := children(b); ok {
+		return collideSpace(space, func(s Shape) (r.Vector2, bool) {
+			return Collide(a, s)
+		})
+	}
+
+	return collideHulls(hullOf(a), hullOf(b))
+}
+
+// collideSpace returns the longest translation vector out of every shape in
+// the space.
+func collideSpace(space *Space, fn func(Shape) (r.Vector2, bool)) (r.Vector2, bool) {
+	var (
+		best  r.Vector2
+		found bool
+	)
+
+	for _, s := range *space {
+		if mtv, ok := fn(s); ok && (!found || mtv.SqrLength() > best.SqrLength()) {
+			best = mtv
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// collideHulls is the separating axis test between two hulls. Every edge
+// normal of both hulls is tried as an axis, along with the axis from a
+// circle's center to the closest point of the other hull, since curves have
+// no edges of their own.
+func collideHulls(a, b hull) (r.Vector2, bool) {
+	if len(a.points) == 0 || len(b.points) == 0 {
+		return r.Vector2{}, false
+	}
+
+	axes := make([]r.Vector2, 0, len(a.points)+len(b.points)+2)
+	axes = appendEdgeNormals(axes, a.points)
+	axes = appendEdgeNormals(axes, b.points)
+
+	if a.radius > 0 {
+		axes = appendClosestAxis(axes, a.points[0], b.points)
+	}
+	if b.radius > 0 {
+		axes = appendClosestAxis(axes, b.points[0], a.points)
+	}
+
+	// Circles with the same center have no axis between them, so push a
+	// straight up out of b.
+	if len(axes) == 0 {
+		axes = append(axes, r.NewVector2(0, -1))
+	}
+
+	var (
+		mtv   r.Vector2
+		depth = float32(math.Inf(1))
+	)
+
+	for _, axis := range axes {
+		minA, maxA := a.project(axis)
+		minB, maxB := b.project(axis)
+
+		// Found a gap between the two hulls, so they can't be overlapping.
+		overlap := float32(math.Min(float64(maxA-minB), float64(maxB-minA)))
+		if overlap <= 0 {
+			return r.Vector2{}, false
+		}
+
+		if overlap < depth {
+			depth = overlap
+			mtv = axis
+
+			// Point the vector away from b.
+			if minA+maxA < minB+maxB {
+				mtv = axis.Negate()
+			}
+		}
+	}
+
+	return mtv.Scale(depth), true
+}
+
+// project returns the smallest and largest points of the hull on the axis.
+func (h hull) project(axis r.Vector2) (float32, float32) {
+	min := h.points[0].DotProduct(axis)
+	max := min
+
+	for _, p := range h.points[1:] {
+		d := p.DotProduct(axis)
+		if d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+	}
+
+	return min - h.radius, max + h.radius
+}
+
+// appendEdgeNormals adds the normal of every edge between the points. A line
+// only has one edge and a single point has none.
+func appendEdgeNormals(axes []r.Vector2, points []r.Vector2) []r.Vector2 {
+	edges := len(points)
+	if edges == 2 {
+		edges = 1
+	}
+
+	for i := 0; i < edges && len(points) > 1; i++ {
+		edge := points[(i+1)%len(points)].Subtract(points[i])
+		if edge.X == 0 && edge.Y == 0 {
+			continue
+		}
+
+		axes = append(axes, r.NewVector2(-edge.Y, edge.X).Normalize())
+	}
+
+	return axes
+}
+
+// appendClosestAxis adds the axis from the center to the closest of the points.
+func appendClosestAxis(axes []r.Vector2, center r.Vector2, points []r.Vector2) []r.Vector2 {
+	closest := points[0]
+	for _, p := range points[1:] {
+		if p.Subtract(center).SqrLength() < closest.Subtract(center).SqrLength() {
+			closest = p
+		}
+	}
+
+	axis := closest.Subtract(center)
+	if axis.X == 0 && axis.Y == 0 {
+		return axes
+	}
+
+	return append(axes, axis.Normalize())
+}
@@ -16,6 +16,14 @@ type Shape interface {
 	RemoveTags(tags ...common.Tag)
 	ClearTags()
 
+	Layer() Layer
+	Mask() Layer
+	SetLayer(l Layer)
+	SetMask(l Layer)
+
+	Material() *Material
+	SetMaterial(m *Material)
+
 	Overlaps(rec r.Rectangle) bool
 	Position() r.Vector2
 	Center() r.Vector2
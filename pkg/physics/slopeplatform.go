@@ -25,6 +25,7 @@ type SlopePlatform struct {
 	landingZone1 *Slope // hangs left
 	landingZone2 *Slope // hangs right
 	slope        *Slope
+	oneWay       bool
 
 	*Space
 }
@@ -51,6 +52,17 @@ func (sp *SlopePlatform) LandingZones() (*Slope, *Slope) {
 	return sp.landingZone1, sp.landingZone2
 }
 
+// SetOneWay flags the platform as one-way, which lets bodies jump through it
+// from below and drop down through it.
+func (sp *SlopePlatform) SetOneWay(oneWay bool) {
+	sp.oneWay = oneWay
+}
+
+// OneWay returns if the platform is one-way.
+func (sp *SlopePlatform) OneWay() bool {
+	return sp.oneWay
+}
+
 // Slope returns a reference to the slope alone.
 func (sp *SlopePlatform) Slope() *Slope {
 	return sp.slope
@@ -174,7 +174,7 @@ func (s *Space) ClearTags() {
 
 // Tags gets all tags from its shapes and returns a big list of them.
 func (s *Space) Tags() []common.Tag {
-	var tmp = &Rectangle{}
+	var tmp = NewBasicShape()
 	for i := range *s {
 		tt := (*s)[i].Tags()
 		for _, t := range tt {
@@ -206,6 +206,53 @@ func (s *Space) RemoveTags(tags ...common.Tag) {
 	}
 }
 
+// Layer returns all the layers that the shapes in the space are on.
+func (s *Space) Layer() Layer {
+	var l Layer
+	for i := range *s {
+		l |= (*s)[i].Layer()
+	}
+	return l
+}
+
+// Mask returns all the layers that the shapes in the space collide with.
+func (s *Space) Mask() Layer {
+	var l Layer
+	for i := range *s {
+		l |= (*s)[i].Mask()
+	}
+	return l
+}
+
+// SetLayer puts all the shapes in the space on the layers given.
+func (s *Space) SetLayer(l Layer) {
+	for i := range *s {
+		(*s)[i].SetLayer(l)
+	}
+}
+
+// SetMask makes all the shapes in the space collide with the layers given.
+func (s *Space) SetMask(l Layer) {
+	for i := range *s {
+		(*s)[i].SetMask(l)
+	}
+}
+
+// Material returns the material of the first shape in the space.
+func (s *Space) Material() *Material {
+	if len(*s) > 0 {
+		return (*s)[0].Material()
+	}
+	return DefaultMaterial()
+}
+
+// SetMaterial makes all the shapes in the space out of the material given.
+func (s *Space) SetMaterial(m *Material) {
+	for i := range *s {
+		(*s)[i].SetMaterial(m)
+	}
+}
+
 // Filter is a custom filterer to remove shapes from a list based on a
 // certain property specified by the user.
 func (s *Space) Filter(filter func(Shape) bool) *Space {
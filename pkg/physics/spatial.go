@@ -1,27 +1,25 @@
 package physics
 
 import (
-	"fmt"
+	"math"
 )
 
 // SpatialHashmap is a data structure to tell what objects are close, if not
 // already colliding with each other.
 // More info: http://hhoppe.com/perfecthash.pdf
 type SpatialHashmap struct {
-	list     []interface{}
-	moveList []interface{}
+	cellSize int
+	getKeys  func(Transformer) []point
 
-	cellSize         int
-	lastTotalCleared int
-	getKeys          func(Transformer) []point
+	hash map[point][]*proxy
 
-	hash map[point][]interface{}
-	// hash map[point][]Transformer
-}
+	// minCell and maxCell are the corners of the cells that have anything in
+	// them, so raycasts can stop once they've left every object behind. They
+	// are found again when stale, after a cell on their edge is emptied.
+	minCell, maxCell point
+	bounded, stale   bool
 
-// SpatialAdder is a custom adder to the spatial hashmap.
-type SpatialAdder interface {
-	Add(w *SpatialHashmap) // custom add function
+	proxySet
 }
 
 // NewSpatialHashmap returns a hashmap with the sensitivity given.
@@ -30,7 +28,7 @@ func NewSpatialHashmap(cellSize int) *SpatialHashmap {
 	s := &SpatialHashmap{
 		cellSize: cellSize,
 		getKeys:  makeKeys(cellSize),
-		hash:     make(map[point][]interface{}),
+		hash:     make(map[point][]*proxy),
 	}
 
 	return s
@@ -40,10 +38,10 @@ func NewSpatialHashmap(cellSize int) *SpatialHashmap {
 // calculating the shift sensitivity to newly inserted objects.
 func makeKeys(shift int) func(Transformer) []point {
 	return func(t Transformer) []point {
-		sx := int(t.Position().X) >> shift
-		sy := int(t.Position().Y) >> shift
-		ex := int(t.MaxPosition().X) >> shift
-		ey := int(t.MaxPosition().Y) >> shift
+		sx := cell(t.Position().X, shift)
+		sy := cell(t.Position().Y, shift)
+		ex := cell(t.MaxPosition().X, shift)
+		ey := cell(t.MaxPosition().Y, shift)
 
 		var x, y int
 		var keys []point
@@ -58,35 +56,23 @@ func makeKeys(shift int) func(Transformer) []point {
 	}
 }
 
+// cell returns the index of the cell that the coordinate is in.
+// The coordinate is floored first so negative coordinates land in the correct
+// cell instead of being truncated towards zero.
+func cell(v float32, shift int) int {
+	return int(math.Floor(float64(v))) >> uint(shift)
+}
+
 // Clear empties the spatial hashmap.
 func (s *SpatialHashmap) Clear() {
-	for key := range s.hash {
-		if len(s.hash[key]) == 0 {
-			delete(s.hash, key)
-		} else {
-			s.hash[key] = []interface{}{}
-		}
-	}
-
-	s.list = []interface{}{}
+	s.hash = make(map[point][]*proxy)
+	s.bounded, s.stale = false, false
+	s.reset()
 }
 
 // InsertI allows from interfaces to be placed in.
 func (s *SpatialHashmap) InsertI(objects ...interface{}) error {
-	for i := range objects {
-		switch t := objects[i].(type) {
-		case SpatialAdder:
-			t.Add(s)
-		case Moveable:
-			s.InsertMoveables(t)
-		case Transformer:
-			s.insertSingle(t)
-		default:
-			return fmt.Errorf("invalid insert: %v", t)
-		}
-	}
-
-	return nil
+	return insertObjects(s, objects...)
 }
 
 // Insert loops through the given transformers and inserts them into the map.
@@ -104,89 +90,162 @@ func (s *SpatialHashmap) InsertSA(sa ...SpatialAdder) {
 	}
 }
 
+// InsertMoveables inserts objects that move around. They're stored just like
+// anything else, so make sure to call Update after they've moved.
 func (s *SpatialHashmap) InsertMoveables(mm ...Moveable) {
 	for i := range mm {
-		s.list = append(s.list, mm[i])
-		s.moveList = append(s.moveList, mm[i])
+		s.insertSingle(mm[i])
 	}
 }
 
 func (s *SpatialHashmap) insertSingle(t Transformer) {
-	// Get the hash key of the object.
-	keys := s.getKeys(t)
-	s.list = append(s.list, t)
+	p := s.add(t)
+	if p == nil {
+		return
+	}
 
-	for i := 0; i < len(keys); i++ {
-		key := keys[i]
+	s.hashProxy(p)
+}
 
-		// If the key already exists, then append.
-		// If not then create the key location.
-		if s.hash[key] != nil {
-			s.hash[key] = append(s.hash[key], t)
-		} else {
-			s.hash[key] = []interface{}{t}
+// hashProxy places the proxy in every cell that its bounds cover.
+func (s *SpatialHashmap) hashProxy(p *proxy) {
+	// Get the hash keys of the object.
+	p.keys = s.getKeys(p.bounds)
+
+	for _, key := range p.keys {
+		if _, ok := s.hash[key]; !ok {
+			s.growCells(key)
 		}
+
+		s.hash[key] = append(s.hash[key], p)
 	}
 }
 
-func (s *SpatialHashmap) Remove(t Transformer) {
-	keys := s.getKeys(t)
-	idxOfT := -1
+// unhashProxy takes the proxy out of all the cells that it was in.
+func (s *SpatialHashmap) unhashProxy(p *proxy) {
+	for _, key := range p.keys {
+		bucket := s.hash[key]
 
-	for i := range s.list {
-		if s.list[i] == t {
-			idxOfT = i
-			break
+		for j := range bucket {
+			if bucket[j] == p {
+				bucket = append(bucket[:j], bucket[j+1:]...)
+				break
+			}
 		}
-	}
 
-	for i := 0; i < len(keys); i++ {
-		key := keys[i]
+		if len(bucket) == 0 {
+			delete(s.hash, key)
 
-		if s.hash[key] != nil {
-			for j := range s.hash[key] {
-				if s.hash[key][j] == t {
-					s.hash[key] = append(s.hash[key][:j], s.hash[key][j+1:]...)
-					break
-				}
+			if key.X() == s.minCell.X() || key.X() == s.maxCell.X() ||
+				key.Y() == s.minCell.Y() || key.Y() == s.maxCell.Y() {
+				s.stale = true
 			}
+		} else {
+			s.hash[key] = bucket
 		}
 	}
 
-	if idxOfT == -1 {
+	p.keys = nil
+}
+
+// growCells makes the occupied cells cover the cell given.
+func (s *SpatialHashmap) growCells(key point) {
+	if !s.bounded {
+		s.minCell, s.maxCell = key, key
+		s.bounded = true
 		return
 	}
 
-	s.list = append(s.list[:idxOfT], s.list[idxOfT+1:]...)
+	for i := range key {
+		if key[i] < s.minCell[i] {
+			s.minCell[i] = key[i]
+		}
+		if key[i] > s.maxCell[i] {
+			s.maxCell[i] = key[i]
+		}
+	}
 }
 
-// NumBuckets returns the number of key locations (buckets.)
-func (s *SpatialHashmap) NumBuckets() int {
-	var count int
-	for key := range s.hash {
-		if len(s.hash[key]) > 0 {
-			count++
+// occupiedCells returns the corners of the cells that have anything in them,
+// and false if the hashmap is empty.
+func (s *SpatialHashmap) occupiedCells() (point, point, bool) {
+	if s.stale {
+		s.bounded, s.stale = false, false
+		for key := range s.hash {
+			s.growCells(key)
 		}
 	}
 
-	return count
+	return s.minCell, s.maxCell, s.bounded
+}
+
+// Remove takes the transformer out of the hashmap.
+func (s *SpatialHashmap) Remove(t Transformer) {
+	if p := s.remove(t); p != nil {
+		s.unhashProxy(p)
+	}
+}
+
+// Update moves the transformer into the cells that it's in now. Nothing
+// happens if it's still in the same cells or it isn't in the hashmap.
+func (s *SpatialHashmap) Update(t Transformer) {
+	p, ok := s.proxies[t]
+	if !ok {
+		return
+	}
+
+	p.bounds = boundsOf(t)
+
+	keys := s.getKeys(p.bounds)
+	if sameKeys(keys, p.keys) {
+		return
+	}
+
+	s.unhashProxy(p)
+	s.hashProxy(p)
+}
+
+func sameKeys(a, b []point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NumBuckets returns the number of key locations (buckets.)
+func (s *SpatialHashmap) NumBuckets() int {
+	return len(s.hash)
 }
 
 // Retrieve queries the spatial hashmap for nearby transforms to the given.
+// If the transform has collision layers then only objects that it can collide
+// with are returned.
 func (s *SpatialHashmap) Retrieve(t Transformer) []interface{} {
-	// func (s *SpatialHashmap) Retrieve(t Transformer) []Transformer {
-	var res []interface{}
-	res = append(res, s.moveList...)
+	return s.RetrieveLayers(t, queryMask(t))
+}
 
+// RetrieveLayers queries the spatial hashmap for nearby transforms that are on
+// any of the layers in the mask. Objects without layers are always returned.
+func (s *SpatialHashmap) RetrieveLayers(t Transformer, mask Layer) []interface{} {
 	if t == nil {
-		return s.list
+		return s.all(mask)
 	}
 
-	keys := s.getKeys(t)
-	for i := 0; i < len(keys); i++ {
-		key := keys[i]
-		if s.hash[key] != nil {
-			res = append(res, s.hash[key]...)
+	s.begin()
+
+	bounds := boundsOf(t)
+
+	var res []interface{}
+	for _, key := range s.getKeys(bounds) {
+		s.stats.Nodes++
+
+		for _, p := range s.hash[key] {
+			res = s.visit(res, p, bounds, mask)
 		}
 	}
 
@@ -0,0 +1,295 @@
+package physics
+
+import (
+	"math"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// sweepEpsilon is how far a collider may already be sunk into a shape while
+// still counting as touching it. This soaks up float error left over from
+// moving a collider exactly to its time of impact.
+const sweepEpsilon = 0.001
+
+// sweepIterations is how many times the time of impact against curved shapes
+// is halved after the step that hit them is found.
+const sweepIterations = 16
+
+var (
+	_ Sweeper = &Rectangle{}
+	_ Sweeper = &Platform{}
+	_ Sweeper = &Slope{}
+	_ Sweeper = &Circle{}
+	_ Sweeper = &ConvexPolygon{}
+)
+
+// Sweeper is any shape that a moving rectangle can be swept against.
+type Sweeper interface {
+	// Sweep moves the rectangle by delta and returns the first point in time
+	// where it touches the shape.
+	Sweep(moving r.Rectangle, delta r.Vector2) (Hit, bool)
+}
+
+// Hit is the result of a sweep.
+type Hit struct {
+	// Time is the fraction (0-1) of the delta travelled before the contact.
+	Time float32
+	// Normal is the surface normal of the shape at the contact point.
+	Normal r.Vector2
+}
+
+// Sweep checks when a moving rectangle would hit this rectangle.
+func (rec *Rectangle) Sweep(moving r.Rectangle, delta r.Vector2) (Hit, bool) {
+	return SweepRectangle(moving, delta, rec.Rectangle)
+}
+
+// Sweep only registers hits from above since platforms are one-way.
+func (p *Platform) Sweep(moving r.Rectangle, delta r.Vector2) (Hit, bool) {
+	// Platforms can only be landed on.
+	if delta.Y <= 0 {
+		return Hit{}, false
+	}
+
+	bottom := moving.Y + moving.Height
+	top := p.Rectangle.Rectangle.Y
+
+	// If the collider was already below the top of the platform then it's
+	// passing through it from below or the side.
+	if bottom > top+sweepEpsilon {
+		return Hit{}, false
+	}
+
+	toi := (top - bottom) / delta.Y
+	if toi < 0 {
+		toi = 0
+	}
+	if toi > 1 {
+		return Hit{}, false
+	}
+
+	// Make sure the collider is above the platform at the time of impact.
+	x := moving.X + delta.X*toi
+	if x+moving.Width <= p.Rectangle.Rectangle.X || x >= p.Rectangle.Rectangle.X+p.Rectangle.Rectangle.Width {
+		return Hit{}, false
+	}
+
+	return Hit{Time: toi, Normal: r.NewVector2(0, -1)}, true
+}
+
+// Sweep checks when the bottom of a moving rectangle would land on the slope.
+// Slopes are only ever stood on, so only downward movement is checked.
+func (l *Slope) Sweep(moving r.Rectangle, delta r.Vector2) (Hit, bool) {
+	if delta.Y <= 0 {
+		return Hit{}, false
+	}
+
+	start, ok := l.surfaceY(moving.X, moving.X+moving.Width)
+	if !ok {
+		return Hit{}, false
+	}
+	end, ok := l.surfaceY(moving.X+delta.X, moving.X+delta.X+moving.Width)
+	if !ok {
+		return Hit{}, false
+	}
+
+	bottom := moving.Y + moving.Height
+	// The collider has to start above the slope and end up below it.
+	if bottom > start+sweepEpsilon || bottom+delta.Y < end {
+		return Hit{}, false
+	}
+
+	// The surface under the collider changes as it moves on the X axis, so
+	// solve for when the bottom of the collider meets the moving surface.
+	toi := float32(0)
+	if closing := delta.Y - (end - start); closing > 0 {
+		toi = (start - bottom) / closing
+	}
+	if toi < 0 {
+		toi = 0
+	}
+	if toi > 1 {
+		return Hit{}, false
+	}
+
+	// Normal that is pointing up and away from the slope.
+	dx, dy := l.Delta()
+	normal := r.NewVector2(dy, -dx).Normalize()
+	if normal.Y > 0 {
+		normal = normal.Negate()
+	}
+
+	return Hit{Time: toi, Normal: normal}, true
+}
+
+// Sweep checks when a moving rectangle would hit the circle.
+func (c *Circle) Sweep(moving r.Rectangle, delta r.Vector2) (Hit, bool) {
+	return sweepHull(hullOf(c), moving, delta)
+}
+
+// Sweep checks when a moving rectangle would hit the polygon.
+func (p *ConvexPolygon) Sweep(moving r.Rectangle, delta r.Vector2) (Hit, bool) {
+	return sweepHull(hullOf(p), moving, delta)
+}
+
+// sweepHull moves the rectangle towards the hull in steps of no more than half
+// of its size, like resolveConvex does, and narrows the time of impact down
+// between the step before the hit and the step that hit.
+func sweepHull(target hull, moving r.Rectangle, delta r.Vector2) (Hit, bool) {
+	overlaps := func(t float32) (r.Vector2, bool) {
+		return collideHulls(rectHull(moving.Move(delta.X*t, delta.Y*t)), target)
+	}
+
+	// Rectangles that start inside of the hull don't count, unless they've
+	// only sunk in by float error.
+	if mtv, ok := overlaps(0); ok {
+		if mtv.Length() > sweepEpsilon {
+			return Hit{}, false
+		}
+
+		return Hit{Time: 0, Normal: mtv.Normalize()}, true
+	}
+
+	steps := hullSteps(moving.Width, moving.Height, delta)
+	for i := 1; i <= steps; i++ {
+		before, after := float32(i-1)/float32(steps), float32(i)/float32(steps)
+		if _, ok := overlaps(after); !ok {
+			continue
+		}
+
+		for j := 0; j < sweepIterations; j++ {
+			mid := (before + after) / 2
+			if _, ok := overlaps(mid); ok {
+				after = mid
+			} else {
+				before = mid
+			}
+		}
+
+		mtv, _ := overlaps(after)
+
+		return Hit{Time: before, Normal: mtv.Normalize()}, true
+	}
+
+	return Hit{}, false
+}
+
+// surfaceY returns the highest point of the slope between minX and maxX.
+func (l *Slope) surfaceY(minX, maxX float32) (float32, bool) {
+	left, right := l.p1, l.p2
+	if left.X > right.X {
+		left, right = right, left
+	}
+
+	// The span doesn't go over the slope at all.
+	if maxX < left.X || minX > right.X {
+		return 0, false
+	}
+
+	// Clamp the span onto the slope.
+	if minX < left.X {
+		minX = left.X
+	}
+	if maxX > right.X {
+		maxX = right.X
+	}
+
+	yAt := func(x float32) float32 {
+		if right.X == left.X {
+			return float32(math.Min(float64(left.Y), float64(right.Y)))
+		}
+		return left.Y + (right.Y-left.Y)*((x-left.X)/(right.X-left.X))
+	}
+
+	// Smaller Y is higher up in screen space.
+	return float32(math.Min(float64(yAt(minX)), float64(yAt(maxX)))), true
+}
+
+// SweepRectangle is a swept AABB test of a moving rectangle against a still
+// one. It returns the time of impact and the normal of the face hit.
+// Rectangles that are already overlapping don't count as a hit.
+func SweepRectangle(moving r.Rectangle, delta r.Vector2, target r.Rectangle) (Hit, bool) {
+	entryX, exitX, ok := sweepAxis(
+		moving.X, moving.Width, target.X, target.Width, delta.X,
+	)
+	if !ok {
+		return Hit{}, false
+	}
+	entryY, exitY, ok := sweepAxis(
+		moving.Y, moving.Height, target.Y, target.Height, delta.Y,
+	)
+	if !ok {
+		return Hit{}, false
+	}
+
+	entry := float32(math.Max(float64(entryX), float64(entryY)))
+	exit := float32(math.Min(float64(exitX), float64(exitY)))
+
+	// No hit if the rectangles stop overlapping before they start, if they
+	// were overlapping to begin with or if the hit happens after this move.
+	if entry >= exit || entry < -sweepEpsilon || entry > 1 {
+		return Hit{}, false
+	}
+
+	var normal r.Vector2
+	if entryX > entryY {
+		normal.X = -sign(delta.X)
+	} else {
+		normal.Y = -sign(delta.Y)
+	}
+
+	if entry < 0 {
+		entry = 0
+	}
+
+	return Hit{Time: entry, Normal: normal}, true
+}
+
+// sweepAxis returns the times when two spans on the same axis start and stop
+// overlapping. If the spans never overlap then false is returned.
+func sweepAxis(pos, size, targetPos, targetSize, delta float32) (float32, float32, bool) {
+	inf := float32(math.Inf(1))
+
+	if delta == 0 {
+		// Without moving the spans must already overlap on this axis.
+		if pos+size <= targetPos || targetPos+targetSize <= pos {
+			return 0, 0, false
+		}
+		return -inf, inf, true
+	}
+
+	var entry, exit float32
+	if delta > 0 {
+		entry = targetPos - (pos + size)
+		exit = (targetPos + targetSize) - pos
+	} else {
+		entry = (targetPos + targetSize) - pos
+		exit = targetPos - (pos + size)
+	}
+
+	return entry / delta, exit / delta, true
+}
+
+// sweptBounds returns a rectangle covering the whole path of a moving rectangle.
+func sweptBounds(rec r.Rectangle, delta r.Vector2) r.Rectangle {
+	if delta.X < 0 {
+		rec.X += delta.X
+	}
+	if delta.Y < 0 {
+		rec.Y += delta.Y
+	}
+
+	rec.Width += float32(math.Abs(float64(delta.X)))
+	rec.Height += float32(math.Abs(float64(delta.Y)))
+
+	return rec
+}
+
+func sign(v float32) float32 {
+	if v < 0 {
+		return -1
+	}
+	if v > 0 {
+		return 1
+	}
+	return 0
+}
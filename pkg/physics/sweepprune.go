@@ -0,0 +1,167 @@
+package physics
+
+import (
+	"sort"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// SweepAndPrune is a broadphase that keeps every object sorted by its left
+// edge. A query only has to look at the objects whose left edge is within
+// reach of the query on the X axis, which works well for levels that are long
+// and flat like most platformer levels are.
+type SweepAndPrune struct {
+	sorted []*proxy
+	// maxWidth is the widest object that has been inserted. Queries use it
+	// to know how far left of themselves objects can start and still reach.
+	maxWidth float32
+
+	proxySet
+}
+
+// NewSweepAndPrune returns an empty sweep and prune broadphase.
+func NewSweepAndPrune() *SweepAndPrune {
+	return &SweepAndPrune{}
+}
+
+// Clear empties the broadphase.
+func (s *SweepAndPrune) Clear() {
+	s.sorted = nil
+	s.maxWidth = 0
+	s.reset()
+}
+
+// InsertI allows from interfaces to be placed in.
+func (s *SweepAndPrune) InsertI(objects ...interface{}) error {
+	return insertObjects(s, objects...)
+}
+
+// Insert adds the transformers into the sorted list.
+func (s *SweepAndPrune) Insert(t ...Transformer) {
+	for i := range t {
+		if p := s.add(t[i]); p != nil {
+			s.insertSorted(p)
+		}
+	}
+}
+
+func (s *SweepAndPrune) insertSorted(p *proxy) {
+	if p.bounds.Width > s.maxWidth {
+		s.maxWidth = p.bounds.Width
+	}
+
+	i := sort.Search(len(s.sorted), func(i int) bool {
+		return s.sorted[i].bounds.X > p.bounds.X
+	})
+
+	s.sorted = append(s.sorted, nil)
+	copy(s.sorted[i+1:], s.sorted[i:])
+	s.sorted[i] = p
+}
+
+// Remove takes the transformer out of the broadphase.
+func (s *SweepAndPrune) Remove(t Transformer) {
+	p := s.remove(t)
+	if p == nil {
+		return
+	}
+
+	if i := s.indexOf(p); i != -1 {
+		s.sorted = append(s.sorted[:i], s.sorted[i+1:]...)
+	}
+}
+
+// indexOf finds where the proxy is in the sorted list.
+func (s *SweepAndPrune) indexOf(p *proxy) int {
+	i := sort.Search(len(s.sorted), func(i int) bool {
+		return s.sorted[i].bounds.X >= p.bounds.X
+	})
+
+	for ; i < len(s.sorted); i++ {
+		if s.sorted[i] == p {
+			return i
+		}
+	}
+
+	// The proxy can only be missing from its spot if something moved
+	// without being updated, so look through everything instead.
+	for i := range s.sorted {
+		if s.sorted[i] == p {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Update moves the transformer to its new spot in the sorted list. Objects
+// usually only move a little bit each update, so the object is shifted over
+// its neighbours instead of being removed and inserted again.
+func (s *SweepAndPrune) Update(t Transformer) {
+	p, ok := s.proxies[t]
+	if !ok {
+		return
+	}
+
+	i := s.indexOf(p)
+	if i == -1 {
+		return
+	}
+
+	p.bounds = boundsOf(t)
+
+	if p.bounds.Width > s.maxWidth {
+		s.maxWidth = p.bounds.Width
+	}
+
+	for i > 0 && s.sorted[i-1].bounds.X > p.bounds.X {
+		s.sorted[i] = s.sorted[i-1]
+		i--
+	}
+	for i < len(s.sorted)-1 && s.sorted[i+1].bounds.X < p.bounds.X {
+		s.sorted[i] = s.sorted[i+1]
+		i++
+	}
+
+	s.sorted[i] = p
+}
+
+// Retrieve returns the objects touching the transformer's bounds.
+func (s *SweepAndPrune) Retrieve(t Transformer) []interface{} {
+	return s.RetrieveLayers(t, queryMask(t))
+}
+
+// RetrieveLayers returns the objects touching the transformer's bounds that
+// are on any of the layers in the mask.
+func (s *SweepAndPrune) RetrieveLayers(t Transformer, mask Layer) []interface{} {
+	if t == nil {
+		return s.all(mask)
+	}
+
+	s.begin()
+
+	bounds := boundsOf(t)
+
+	// Nothing that starts further left than the widest object can reach.
+	start := sort.Search(len(s.sorted), func(i int) bool {
+		return s.sorted[i].bounds.X >= bounds.X-s.maxWidth
+	})
+
+	var res []interface{}
+	for i := start; i < len(s.sorted) && s.sorted[i].bounds.X <= bounds.X+bounds.Width; i++ {
+		s.stats.Nodes++
+		res = s.visit(res, s.sorted[i], bounds, mask)
+	}
+
+	return res
+}
+
+// Raycast returns the nearest shape that the ray hits.
+func (s *SweepAndPrune) Raycast(origin, dir r.Vector2, maxDist float32, filter QueryFilter) (RayHit, bool) {
+	return raycast(s, origin, dir, maxDist, filter)
+}
+
+// ShapeCast returns the nearest shape that the rectangle would hit.
+func (s *SweepAndPrune) ShapeCast(rec r.Rectangle, delta r.Vector2, filter QueryFilter) (RayHit, bool) {
+	return shapeCast(s, rec, delta, filter)
+}
@@ -1,12 +1,14 @@
 package physics
 
 import (
+	"github.com/damienfamed75/rayrem/pkg/common"
 	"github.com/damienfamed75/rayrem/pkg/msg"
 	r "github.com/lachee/raylib-goplus/raylib"
 )
 
 // Zone is a rectangle that sends a signal to the mailbox given with the
-// specified message type.
+// specified message type. Messages are sent with the phase of the overlap, so
+// listen to Enter.Of(msgType), Stay.Of(msgType) or Exit.Of(msgType).
 type Zone struct {
 	mailbox *msg.MessageManager
 	msgType string
@@ -24,10 +26,13 @@ func NewZone(x, y, w, h float32, mailbox *msg.MessageManager, msgType string) *Z
 	return z
 }
 
-func (z *Zone) dispatchMessage(entity *Space, overlap r.Rectangle) {
+func (z *Zone) dispatchMessage(phase Phase, entity *Space, overlap r.Rectangle, normal r.Vector2) {
 	msg := &ZoneMessage{
+		Phase:   phase,
 		Entity:  entity,
+		Tags:    entity.Tags(),
 		Overlap: overlap,
+		Normal:  normal,
 		msgType: z.msgType,
 	}
 
@@ -36,13 +41,20 @@ func (z *Zone) dispatchMessage(entity *Space, overlap r.Rectangle) {
 
 // ZoneMessage stores the information through the message in the zone's mailbox.
 type ZoneMessage struct {
-	Entity  *Space
+	Phase Phase
+	// Entity is the collision space of the body overlapping the zone.
+	Entity *Space
+	// Tags are the tags of the entity.
+	Tags []common.Tag
+	// Overlap is the last known overlap between the entity and the zone.
 	Overlap r.Rectangle
+	// Normal is the side of the zone that the entity is overlapping from.
+	Normal r.Vector2
 
 	msgType string
 }
 
-// Type returns the message type.
+// Type returns the message type with the phase of the overlap.
 func (z *ZoneMessage) Type() string {
-	return z.msgType
+	return z.Phase.Of(z.msgType)
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/fsm"
+	"github.com/damienfamed75/rayrem/pkg/input"
 	"github.com/damienfamed75/rayrem/pkg/physics"
 
 	r "github.com/lachee/raylib-goplus/raylib"
@@ -18,28 +20,53 @@ var (
 type Player struct {
 	*physics.Actor
 
-	doubleJumpPerformed bool
-	jumpHeight          float32
-	friction            float32
+	states  *fsm.Machine
+	actions *input.Map
 
-	solids *physics.SpatialHashmap
+	// coyoteTimer is how much longer the player can jump after leaving the
+	// ground and jumpBuffer is how much longer an early jump press is kept.
+	coyoteTimer float32
+	jumpBuffer  float32
+	// airJumps is how many jumps the player has left before landing.
+	airJumps int
+	// jumping is true while the player is rising from a jump that can still
+	// be cut short.
+	jumping bool
+	// onWall and wallSide are from the rigidbody's last update.
+	onWall   bool
+	wallSide common.Direction
+	// wallJumpLock is how much longer the player can't move after a wall jump.
+	wallJumpLock float32
+
+	jumpHeight   float32
+	acceleration float32
+
+	solids physics.Broadphase
 }
 
 // New creates a player at the position specified.
 // the world object is updated dynamically because the ground elements aren't
 // stored by value or locally at all.
-func New(x, y float32, solids *physics.SpatialHashmap) (*Player, error) {
+func New(x, y float32, solids physics.Broadphase) (*Player, error) {
+	return NewWith(x, y, solids, common.Assets)
+}
+
+// NewWith creates a player whose spritesheet and texture are loaded by the
+// sprite loader given.
+func NewWith(x, y float32, solids physics.Broadphase, sprites common.SpriteLoader) (*Player, error) {
 	p := &Player{
-		friction:   common.Config.Player.Friction,
-		jumpHeight: common.Config.Player.JumpHeight,
-		solids:     solids,
+		acceleration: common.Config.Player.Acceleration,
+		jumpHeight:   common.Config.Player.JumpHeight,
+		actions:      input.Actions,
+		solids:       solids,
 	}
 
-	ase, err := common.LoadSpritesheet(common.Config.Player.Spritesheet)
+	ase, err := sprites.Spritesheet(common.Config.Player.Spritesheet)
 	if err != nil {
 		return nil, fmt.Errorf("aseprite: %w", err)
 	}
 
+	// The first frame is used to size the player's collision.
 	ase.Play("idle")
 
 	// Create the collision areas of the player.
@@ -49,16 +76,23 @@ func New(x, y float32, solids *physics.SpatialHashmap) (*Player, error) {
 	)
 
 	// Prepare the player's actor and basic entity.
-	p.Actor, err = physics.NewActor(
+	p.Actor, err = physics.NewActorWith(
 		collision, solids,
 		r.NewVector2(common.Config.Player.MaxSpeed.X, common.Config.Player.MaxSpeed.Y),
-		ase,
+		ase, sprites,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("basic entity: %w", err)
 	}
 
+	p.Knockback = common.Config.Player.Knockback
 	p.Space.AddTags(common.TagPlayer)
+	p.Rigidbody.SetLayer(physics.LayerPlayer)
+
+	p.states, err = p.newStates()
+	if err != nil {
+		return nil, fmt.Errorf("states: %w", err)
+	}
 
 	return p, nil
 }
@@ -5,6 +5,7 @@ package player
 import (
 	"fmt"
 
+	"github.com/damienfamed75/rayrem/pkg/common"
 	"github.com/damienfamed75/rayrem/pkg/physics"
 
 	r "github.com/lachee/raylib-goplus/raylib"
@@ -22,15 +23,18 @@ func (p *Player) Draw() {
 	)
 
 	r.DrawText(
-		fmt.Sprintf("pos[%.2f, %.2f]", p.Rigidbody.Position().X, p.Rigidbody.Position().Y),
+		fmt.Sprintf("pos[%.2f, %.2f] %s", p.Rigidbody.Position().X, p.Rigidbody.Position().Y, p.State()),
 		int(p.Position().X), int(p.Position().Y)+p.Ase.FrameBoundaries().Height,
 		5, r.White,
 	)
 
+	// Velocity is per second, so scale it down to a single update.
+	motion := p.Velocity().Scale(1 / float32(common.Config.Game.TickRate))
+
 	for i := range *p.Rigidbody.Space {
 		collider := (*p.Rigidbody.Space)[i].(*physics.Rectangle).Rectangle
 		possible := p.solids.Retrieve(collider.Move(
-			p.Velocity().X, p.Velocity().Y,
+			motion.X, motion.Y,
 		))
 
 		var tpossible []physics.Transformer
@@ -1,7 +1,11 @@
 package player
 
 import (
+	"log"
+
 	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+	"github.com/damienfamed75/rayrem/pkg/physics"
 
 	r "github.com/lachee/raylib-goplus/raylib"
 )
@@ -12,48 +16,79 @@ func (p *Player) SetPosition(x, y float32) {
 	p.Rigidbody.SetPosition(x, y)
 }
 
-// TakeDamage doesn't do anything by default.
-// TODO
-func (p *Player) TakeDamage() {}
+// SetSolids takes the player out of its world and makes it collide with
+// another one, like when the player goes from one level to another. The
+// player still has to be inserted into the new world.
+func (p *Player) SetSolids(solids physics.Broadphase) {
+	p.solids = solids
+	p.Rigidbody.SetSolids(solids)
+}
+
+// TakeDamage knocks the player away from where the damage came from and puts
+// them into the hurt state.
+// TODO: health
+func (p *Player) TakeDamage(from r.Vector2) {
+	p.Actor.TakeDamage(from)
+
+	// The hurt animation was checked when the player was created, so this
+	// shouldn't fail.
+	if err := p.states.Set(StateHurt); err != nil {
+		log.Printf("player state: %v", err)
+	}
+}
 
-// Update updates the default basic entity and checks for movement and sends it
-// to the Rigidbody.
+// Update updates the default basic entity and then lets the state machine
+// move the player.
+// All of the player's speeds are in units per second and are scaled by the
+// delta time, so the player moves the same at any tick rate.
 func (p *Player) Update(dt float32) {
 	p.Actor.Update(dt)
 
-	if p.doubleJumpPerformed {
-		p.doubleJumpPerformed = !p.Rigidbody.OnGround()
+	cfg := &common.Config.Player
+
+	if p.Rigidbody.OnGround() {
+		p.coyoteTimer = cfg.CoyoteTime
+		p.airJumps = cfg.AirJumps
+	} else if p.coyoteTimer > 0 {
+		p.coyoteTimer -= dt
+	}
+
+	if p.actions.Pressed(input.Jump) {
+		p.jumpBuffer = cfg.JumpBuffer
+	}
+
+	if p.wallJumpLock > 0 {
+		p.wallJumpLock -= dt
 	}
 
-	if p.Velocity().X > p.friction {
-		p.AddVelocity(-p.friction, 0)
-		p.Facing = common.Right
-		p.Ase.Play("run")
-	} else if p.Velocity().X < -p.friction {
-		p.AddVelocity(+p.friction, 0)
-		p.Facing = common.Left
-		p.Ase.Play("run")
-	} else {
-		p.SetVelocity(0, p.Velocity().Y)
-		p.Ase.Play("idle")
+	p.wallSide, p.onWall = p.Rigidbody.OnWall()
+	p.wallSlide()
+
+	// Every animation was checked when the player was created, so this
+	// shouldn't fail.
+	if err := p.states.Update(dt); err != nil {
+		log.Printf("player state: %v", err)
 	}
 
-	// If the player is holding right.
-	if r.IsKeyDown(common.Controls.Right) {
-		p.AddVelocity(1, 0)
+	if p.jumpBuffer > 0 {
+		p.jumpBuffer -= dt
 	}
+}
+
+// wallSlide slows the player's fall while they're pushing against a wall in
+// the air.
+func (p *Player) wallSlide() {
+	gravity := common.Config.Game.Gravity
 
-	// If the player is holding left.
-	if r.IsKeyDown(common.Controls.Left) {
-		p.AddVelocity(-1, 0)
+	sliding := p.onWallInAir() && p.Velocity().Y > 0
+	if !sliding {
+		p.Rigidbody.SetGravity(gravity)
+		return
 	}
-	// If the player is trying to jump.
-	if r.IsKeyPressed(common.Controls.Jump) {
-		if p.Rigidbody.OnGround() {
-			p.SetVelocity(p.Velocity().X, -p.jumpHeight)
-		} else if !p.Rigidbody.OnGround() && !p.doubleJumpPerformed {
-			p.SetVelocity(p.Velocity().X, -p.jumpHeight)
-			p.doubleJumpPerformed = true
-		}
+
+	p.Rigidbody.SetGravity(gravity * common.Config.Player.WallSlide.Gravity)
+
+	if max := common.Config.Player.WallSlide.MaxSpeed; p.Velocity().Y > max {
+		p.SetVelocity(p.Velocity().X, max)
 	}
 }
@@ -0,0 +1,11 @@
+package player
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/input"
+)
+
+// SetInput changes the actions that the player reads from. Whoever owns the
+// map has to update it once per update.
+func (p *Player) SetInput(actions *input.Map) {
+	p.actions = actions
+}
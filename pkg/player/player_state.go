@@ -0,0 +1,191 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/fsm"
+	"github.com/damienfamed75/rayrem/pkg/input"
+)
+
+// States that the player can be in.
+const (
+	StateIdle       = "idle"
+	StateRun        = "run"
+	StateJump       = "jump"
+	StateFall       = "fall"
+	StateDoubleJump = "double-jump"
+	StateAttack     = "attack"
+	StateHurt       = "hurt"
+)
+
+// newStates creates the player's state machine and starts it in the idle state.
+func (p *Player) newStates() (*fsm.Machine, error) {
+	m := fsm.New(p.Ase)
+
+	// player.json doesn't have any animations for being in the air yet.
+	states := []*fsm.State{
+		{Name: StateIdle, Animation: "idle", Update: p.updateGround},
+		{Name: StateRun, Animation: "run", Update: p.updateGround},
+		{Name: StateJump, Animation: "idle", Enter: p.enterJump, Update: p.updateAir},
+		{Name: StateFall, Animation: "idle", Update: p.updateAir},
+		{Name: StateDoubleJump, Animation: "idle", Enter: p.enterDoubleJump, Update: p.updateAir},
+		{Name: StateAttack, Animation: "attack", Update: p.move},
+		{Name: StateHurt, Animation: "damage"},
+	}
+
+	for _, s := range states {
+		if !p.Ase.HasAnimation(s.Animation) {
+			return nil, fmt.Errorf("state %q: missing animation %q", s.Name, s.Animation)
+		}
+	}
+
+	m.Add(states...)
+
+	var (
+		ground = []string{StateIdle, StateRun}
+		air    = []string{StateJump, StateFall, StateDoubleJump}
+		free   = append(ground, air...)
+	)
+
+	err := m.AddTransition(
+		fsm.Transition{From: free, To: StateJump, Guard: p.canJump},
+		fsm.Transition{From: air, To: StateDoubleJump, Guard: p.canAirJump},
+		fsm.Transition{From: free, To: StateAttack, Guard: func() bool {
+			return p.actions.Pressed(input.Shoot)
+		}},
+		fsm.Transition{From: []string{StateJump, StateDoubleJump}, To: StateFall, Guard: func() bool {
+			return p.Velocity().Y >= 0
+		}},
+		fsm.Transition{From: air, To: StateIdle, Guard: p.landed},
+		fsm.Transition{From: ground, To: StateFall, Guard: func() bool {
+			return !p.Rigidbody.OnGround()
+		}},
+		fsm.Transition{From: []string{StateIdle}, To: StateRun, Guard: func() bool {
+			return p.Velocity().X != 0
+		}},
+		fsm.Transition{From: []string{StateRun}, To: StateIdle, Guard: func() bool {
+			return p.Velocity().X == 0
+		}},
+		// Attacks and getting hurt both last until they're over.
+		fsm.Transition{From: []string{StateAttack}, To: StateIdle, Guard: func() bool {
+			return p.Ase.AnimationFinished() && p.Rigidbody.OnGround()
+		}},
+		fsm.Transition{From: []string{StateAttack}, To: StateFall, Guard: p.Ase.AnimationFinished},
+		fsm.Transition{From: []string{StateHurt}, To: StateIdle, Guard: func() bool {
+			return p.hurtOver() && p.Rigidbody.OnGround()
+		}},
+		fsm.Transition{From: []string{StateHurt}, To: StateFall, Guard: p.hurtOver},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("transitions: %w", err)
+	}
+
+	if err := m.Set(StateIdle); err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+
+	return m, nil
+}
+
+// State returns the name of the state that the player is in.
+func (p *Player) State() string {
+	return p.states.Current()
+}
+
+// canJump returns if a jump was pressed recently while the player is able to
+// jump off of the ground or a wall.
+func (p *Player) canJump() bool {
+	return p.jumpBuffer > 0 && (p.coyoteTimer > 0 || p.onWallInAir())
+}
+
+// canAirJump returns if the player is jumping in the air. Only fresh presses
+// use air jumps, buffered ones wait for the ground.
+func (p *Player) canAirJump() bool {
+	return p.actions.Pressed(input.Jump) && p.airJumps > 0
+}
+
+// landed returns if the player has come back down on the ground.
+func (p *Player) landed() bool {
+	return p.Rigidbody.OnGround() && p.Velocity().Y >= 0
+}
+
+func (p *Player) hurtOver() bool {
+	return p.states.Elapsed() >= common.Config.Player.HurtTime
+}
+
+func (p *Player) onWallInAir() bool {
+	return p.onWall && !p.Rigidbody.OnGround()
+}
+
+func (p *Player) enterJump() {
+	cfg := &common.Config.Player
+
+	if p.onWallInAir() {
+		// Kick off away from the wall.
+		p.SetVelocity(-float32(p.wallSide)*cfg.WallJump.X, -cfg.WallJump.Y)
+		p.Facing = -p.wallSide
+		p.wallJumpLock = cfg.WallJump.Lock
+	} else {
+		p.SetVelocity(p.Velocity().X, -p.jumpHeight)
+	}
+
+	p.jumping = true
+	p.jumpBuffer = 0
+	p.coyoteTimer = 0
+}
+
+func (p *Player) enterDoubleJump() {
+	p.SetVelocity(p.Velocity().X, -p.jumpHeight)
+
+	p.airJumps--
+	p.jumping = true
+	p.jumpBuffer = 0
+}
+
+func (p *Player) updateGround(dt float32) {
+	p.move(dt)
+
+	// If the player is trying to drop down through a one-way platform.
+	if p.actions.Pressed(input.Down) && p.Rigidbody.OnGround() {
+		p.Rigidbody.DropThrough(p.Rigidbody.Ground())
+	}
+}
+
+func (p *Player) updateAir(dt float32) {
+	p.move(dt)
+
+	if p.Velocity().Y >= 0 {
+		p.jumping = false
+	}
+
+	// Cut the jump short if the button was let go of while still rising.
+	if p.jumping && !p.actions.Held(input.Jump) {
+		p.SetVelocity(p.Velocity().X, p.Velocity().Y*common.Config.Player.JumpCutoff)
+		p.jumping = false
+	}
+}
+
+// move speeds the player up in the direction that's being held.
+func (p *Player) move(dt float32) {
+	// Surfaces like ice and mud change how fast the player can speed up.
+	acceleration := p.acceleration * dt
+	if m := p.Rigidbody.GroundMaterial(); m != nil {
+		acceleration *= m.Acceleration
+	}
+
+	if p.wallJumpLock > 0 {
+		acceleration = 0
+	}
+
+	// Gamepad sticks only speed the player up as far as they're pushed.
+	p.AddVelocity(acceleration*p.actions.Axis(input.Left, input.Right), 0)
+
+	// Friction is applied by the rigidbody, so only the direction matters.
+	switch {
+	case p.Velocity().X > 0:
+		p.Facing = common.Right
+	case p.Velocity().X < 0:
+		p.Facing = common.Left
+	}
+}
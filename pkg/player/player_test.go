@@ -0,0 +1,198 @@
+package player
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/damienfamed75/aseprite"
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+	"github.com/damienfamed75/rayrem/pkg/physics"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// spritesheet has every animation that the player needs, with 16x16 frames.
+// Its frames are keyed by name like the exported assets, since aseprite only
+// turns the durations from milliseconds into seconds for that layout.
+const spritesheet = `{
+	"frames": {
+		"player 0.aseprite": {"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "duration": 100},
+		"player 1.aseprite": {"frame": {"x": 16, "y": 0, "w": 16, "h": 16}, "duration": 100},
+		"player 2.aseprite": {"frame": {"x": 32, "y": 0, "w": 16, "h": 16}, "duration": 50},
+		"player 3.aseprite": {"frame": {"x": 48, "y": 0, "w": 16, "h": 16}, "duration": 50},
+		"player 4.aseprite": {"frame": {"x": 64, "y": 0, "w": 16, "h": 16}, "duration": 100}
+	},
+	"meta": {
+		"image": "player.png",
+		"size": {"w": 80, "h": 16},
+		"frameTags": [
+			{"name": "idle", "from": 0, "to": 0, "direction": "forward"},
+			{"name": "run", "from": 1, "to": 1, "direction": "forward"},
+			{"name": "attack", "from": 2, "to": 3, "direction": "forward"},
+			{"name": "damage", "from": 4, "to": 4, "direction": "forward"}
+		]
+	}
+}`
+
+// sprites loads the spritesheet above without any images or raylib.
+type sprites struct{}
+
+func (sprites) Spritesheet(fileName string) (*aseprite.File, error) {
+	return aseprite.NewFile([]byte(spritesheet))
+}
+
+func (sprites) Texture(ase *aseprite.File) (r.Texture2D, error) {
+	return r.Texture2D{Width: 80, Height: 16}, nil
+}
+
+const dt = 1.0 / 60
+
+// harness is a player standing on a floor with scripted controls.
+type harness struct {
+	t       *testing.T
+	player  *Player
+	source  *input.Scripted
+	actions *input.Map
+}
+
+func newHarness(t *testing.T) *harness {
+	raw, err := ioutil.ReadFile("../../config/settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(raw, &common.Config); err != nil {
+		t.Fatal(err)
+	}
+
+	solids := physics.NewSpatialHashmap(6)
+	solids.Insert(physics.NewRectangle(-1000, 100, 2000, 100))
+
+	p, err := NewWith(0, 84, solids, sprites{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Add(solids)
+
+	h := &harness{
+		t:      t,
+		player: p,
+		source: input.NewScripted(),
+	}
+
+	h.actions = input.NewMap(h.source)
+	h.actions.Bind(input.Left, input.KeyBinding(r.KeyLeft))
+	h.actions.Bind(input.Right, input.KeyBinding(r.KeyRight))
+	h.actions.Bind(input.Jump, input.KeyBinding(r.KeyUp))
+	h.actions.Bind(input.Shoot, input.KeyBinding(r.KeyC))
+	p.SetInput(h.actions)
+
+	// Land on the floor.
+	h.step(10)
+	h.expect(StateIdle)
+
+	return h
+}
+
+// step updates the controls and the player a number of times.
+func (h *harness) step(n int) {
+	for i := 0; i < n; i++ {
+		h.actions.Update()
+		h.player.Update(dt)
+	}
+}
+
+// until updates the player until it's in the state given.
+func (h *harness) until(state string, max int) {
+	h.t.Helper()
+
+	for i := 0; i < max; i++ {
+		if h.player.State() == state {
+			return
+		}
+
+		h.step(1)
+	}
+
+	h.t.Fatalf("player is %q after %d updates, want %q", h.player.State(), max, state)
+}
+
+func (h *harness) expect(state string) {
+	h.t.Helper()
+
+	if h.player.State() != state {
+		h.t.Fatalf("player is %q, want %q", h.player.State(), state)
+	}
+}
+
+// press holds a key for a single update.
+func (h *harness) press(key r.Key) {
+	h.source.SetKey(key, true)
+	h.step(1)
+	h.source.SetKey(key, false)
+}
+
+func TestTransitions(t *testing.T) {
+	h := newHarness(t)
+
+	h.source.SetKey(r.KeyRight, true)
+	h.until(StateRun, 5)
+
+	h.source.SetKey(r.KeyUp, true)
+	h.step(1)
+	h.expect(StateJump)
+
+	// Holding jump keeps the full jump until the player starts falling.
+	h.until(StateFall, 120)
+
+	if h.player.Velocity().Y < 0 {
+		t.Errorf("player is falling while moving up at %v", h.player.Velocity().Y)
+	}
+
+	h.source.SetKey(r.KeyUp, false)
+	h.step(1)
+	h.press(r.KeyUp)
+	h.expect(StateDoubleJump)
+
+	// The player lands and keeps running.
+	h.until(StateRun, 240)
+
+	h.source.SetKey(r.KeyRight, false)
+	h.until(StateIdle, 240)
+
+	h.press(r.KeyC)
+	h.expect(StateAttack)
+
+	// The attack is over once its animation has played.
+	h.until(StateIdle, 60)
+
+	// Damage from the left knocks the player up and to the right.
+	start := h.player.Position()
+	h.player.TakeDamage(start.Add(r.NewVector2(-10, 8)))
+	h.expect(StateHurt)
+
+	h.step(1)
+	if v := h.player.Velocity(); v.X <= 0 || v.Y >= 0 {
+		t.Errorf("player was knocked back with a velocity of %v", v)
+	}
+
+	h.until(StateIdle, 120)
+}
+
+func TestAirJumpsRunOut(t *testing.T) {
+	h := newHarness(t)
+
+	h.press(r.KeyUp)
+	h.expect(StateJump)
+
+	h.step(5)
+	h.press(r.KeyUp)
+	h.expect(StateDoubleJump)
+
+	// Every air jump has been used, so pressing again does nothing.
+	h.step(5)
+	h.press(r.KeyUp)
+	h.expect(StateDoubleJump)
+}
@@ -0,0 +1,65 @@
+package replay
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/input"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var _ input.ActionSource = &Playback{}
+
+// Playback is an input source that plays back a replay one frame per update.
+// Once the replay is over nothing is held.
+type Playback struct {
+	replay *Replay
+	tick   int
+}
+
+// NewPlayback returns a source that plays the replay from the start.
+func NewPlayback(rp *Replay) *Playback {
+	return &Playback{replay: rp}
+}
+
+// ReadActions sets the actions to the next frame of the replay.
+func (p *Playback) ReadActions(values map[input.Action]float32) {
+	if p.Done() {
+		return
+	}
+
+	frame := p.replay.Frames[p.tick]
+	for i, a := range p.replay.Actions {
+		values[a] = frameValue(frame, i)
+	}
+
+	p.tick++
+}
+
+// Tick returns how many frames have been played.
+func (p *Playback) Tick() int {
+	return p.tick
+}
+
+// Done returns if every frame has been played.
+func (p *Playback) Done() bool {
+	return p.tick >= len(p.replay.Frames)
+}
+
+// Replay returns the replay that's being played.
+func (p *Playback) Replay() *Replay {
+	return p.replay
+}
+
+// KeyDown is always false, since replays only know about actions.
+func (p *Playback) KeyDown(key r.Key) bool {
+	return false
+}
+
+// ButtonDown is always false, since replays only know about actions.
+func (p *Playback) ButtonDown(button r.GamepadButton) bool {
+	return false
+}
+
+// AxisValue is always 0, since replays only know about actions.
+func (p *Playback) AxisValue(axis r.GamepadAxis) float32 {
+	return 0
+}
@@ -0,0 +1,54 @@
+package replay
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Recorder records the actions of an input map every update. Set its Record
+// method as the map's OnUpdate to start recording.
+type Recorder struct {
+	replay *Replay
+}
+
+// NewRecorder returns a recorder for a game that starts in the mode given.
+// The seed and config are the ones that are loaded when it's created.
+func NewRecorder(mode common.Mode) *Recorder {
+	return &Recorder{
+		replay: &Replay{
+			Seed:       common.Seed(),
+			ConfigHash: common.ConfigHash(),
+			TickRate:   common.Config.Game.TickRate,
+			Mode:       mode,
+		},
+	}
+}
+
+// Record adds the values of the map's actions as the next frame.
+func (rec *Recorder) Record(m *input.Map) {
+	// Actions can be bound in the middle of a recording, so add any new ones.
+	if actions := m.Actions(); len(actions) > len(rec.replay.Actions) {
+		rec.replay.Actions = append(rec.replay.Actions, actions[len(rec.replay.Actions):]...)
+	}
+
+	frame := make([]float32, len(rec.replay.Actions))
+	for i, a := range rec.replay.Actions {
+		frame[i] = m.Value(a)
+	}
+
+	rec.replay.Frames = append(rec.replay.Frames, frame)
+}
+
+// Ticks returns how many updates have been recorded.
+func (rec *Recorder) Ticks() int {
+	return len(rec.replay.Frames)
+}
+
+// Finish stops the recording with the player at the position given and
+// returns the replay.
+func (rec *Recorder) Finish(final r.Vector2) *Replay {
+	rec.replay.Final = final
+	return rec.replay
+}
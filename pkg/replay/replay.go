@@ -0,0 +1,327 @@
+// Package replay records the actions that are held every update so they can
+// be played back later to reproduce exactly what happened. The game updates
+// at a fixed rate, so the same actions with the same seed and config always
+// end up in the same place.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// magic is at the start of every replay file.
+const magic = "RRPL"
+
+// version is the version of the replay file format.
+const version = 1
+
+// maxActions and maxTicks limit how big a replay can be, so broken files
+// don't allocate huge amounts of memory.
+const (
+	maxActions = 256
+	maxTicks   = 1 << 24
+)
+
+// Replay is everything needed to play back a recording.
+type Replay struct {
+	// Seed is what common.Random was seeded with.
+	Seed int64
+	// ConfigHash is the hash of the debug config that was used.
+	ConfigHash [sha256.Size]byte
+	TickRate   int
+	// Mode is the scene that the recording started in.
+	Mode common.Mode
+
+	// Actions are the actions in the order their values are in each frame.
+	Actions []input.Action
+	// Frames are the values of the actions on every update.
+	Frames [][]float32
+
+	// Final is where the player was when the recording stopped, which
+	// playback can be checked against.
+	Final r.Vector2
+}
+
+// Check returns an error if the replay was recorded with a different config
+// than the one that's loaded, since it won't play back the same.
+func (rp *Replay) Check() error {
+	if rp.TickRate != common.Config.Game.TickRate {
+		return fmt.Errorf("recorded at %d ticks per second, but the game runs at %d",
+			rp.TickRate, common.Config.Game.TickRate)
+	}
+
+	if rp.ConfigHash != common.ConfigHash() {
+		return errors.New("recorded with a different config")
+	}
+
+	return nil
+}
+
+// Duration returns how many seconds long the replay is.
+func (rp *Replay) Duration() float32 {
+	if rp.TickRate <= 0 {
+		return 0
+	}
+
+	return float32(len(rp.Frames)) / float32(rp.TickRate)
+}
+
+// Load reads a replay file.
+func Load(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open replay: %w", err)
+	}
+	defer f.Close()
+
+	rp, err := Decode(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return rp, nil
+}
+
+// Save writes the replay to a file.
+func (rp *Replay) Save(path string) error {
+	var buf bytes.Buffer
+	if err := rp.Encode(&buf); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write replay: %w", err)
+	}
+
+	return nil
+}
+
+// Encode writes the replay in its file format. Frames are run length encoded
+// since actions are usually held for many updates in a row.
+func (rp *Replay) Encode(w io.Writer) error {
+	e := &encoder{w: w}
+
+	e.bytes([]byte(magic))
+	e.bytes([]byte{version})
+	e.varint(rp.Seed)
+	e.bytes(rp.ConfigHash[:])
+	e.uvarint(uint64(rp.TickRate))
+	e.uvarint(uint64(rp.Mode))
+	e.float(rp.Final.X)
+	e.float(rp.Final.Y)
+
+	e.uvarint(uint64(len(rp.Actions)))
+	for _, a := range rp.Actions {
+		e.uvarint(uint64(len(a)))
+		e.bytes([]byte(a))
+	}
+
+	runs := rp.runs()
+	e.uvarint(uint64(len(runs)))
+	for _, run := range runs {
+		e.uvarint(uint64(run.ticks))
+		for i := range rp.Actions {
+			e.float(frameValue(run.frame, i))
+		}
+	}
+
+	return e.err
+}
+
+// run is a frame that's repeated for a number of ticks.
+type run struct {
+	frame []float32
+	ticks int
+}
+
+// runs groups the frames that are the same in a row.
+func (rp *Replay) runs() []run {
+	var runs []run
+
+	for _, f := range rp.Frames {
+		if n := len(runs); n > 0 && rp.sameFrame(runs[n-1].frame, f) {
+			runs[n-1].ticks++
+			continue
+		}
+
+		runs = append(runs, run{frame: f, ticks: 1})
+	}
+
+	return runs
+}
+
+// sameFrame returns if two frames have the same value for every action.
+func (rp *Replay) sameFrame(a, b []float32) bool {
+	for i := range rp.Actions {
+		if frameValue(a, i) != frameValue(b, i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// frameValue returns the value of an action in a frame. Frames recorded
+// before an action was bound are shorter, so the action wasn't held.
+func frameValue(frame []float32, i int) float32 {
+	if i < len(frame) {
+		return frame[i]
+	}
+
+	return 0
+}
+
+// Decode reads a replay in its file format.
+func Decode(rd io.Reader) (*Replay, error) {
+	br, ok := rd.(byteReader)
+	if !ok {
+		br = bufio.NewReader(rd)
+	}
+	d := &decoder{r: br}
+
+	if string(d.bytes(len(magic))) != magic {
+		if d.err != nil {
+			return nil, d.err
+		}
+		return nil, errors.New("not a replay file")
+	}
+
+	if v := d.bytes(1); d.err == nil && v[0] != version {
+		return nil, fmt.Errorf("unsupported replay version: %d", v[0])
+	}
+
+	rp := &Replay{}
+	rp.Seed = d.varint()
+	copy(rp.ConfigHash[:], d.bytes(sha256.Size))
+	rp.TickRate = int(d.uvarint())
+	rp.Mode = common.Mode(d.uvarint())
+	rp.Final.X = d.float()
+	rp.Final.Y = d.float()
+
+	actions := d.uvarint()
+	if actions > maxActions {
+		return nil, fmt.Errorf("too many actions: %d", actions)
+	}
+
+	for i := uint64(0); i < actions && d.err == nil; i++ {
+		n := d.uvarint()
+		if n > 64 {
+			return nil, fmt.Errorf("action name too long: %d", n)
+		}
+
+		rp.Actions = append(rp.Actions, input.Action(d.bytes(int(n))))
+	}
+
+	runs := d.uvarint()
+	for i := uint64(0); i < runs && d.err == nil; i++ {
+		ticks := d.uvarint()
+		if ticks > maxTicks-uint64(len(rp.Frames)) {
+			return nil, errors.New("replay is too long")
+		}
+
+		frame := make([]float32, len(rp.Actions))
+		for j := range frame {
+			frame[j] = d.float()
+		}
+
+		for t := uint64(0); t < ticks && d.err == nil; t++ {
+			rp.Frames = append(rp.Frames, frame)
+		}
+	}
+
+	if d.err != nil {
+		if d.err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, d.err
+	}
+
+	return rp, nil
+}
+
+// encoder writes values and keeps the first error that happens.
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *encoder) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.bytes(buf[:binary.PutUvarint(buf[:], v)])
+}
+
+func (e *encoder) varint(v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	e.bytes(buf[:binary.PutVarint(buf[:], v)])
+}
+
+func (e *encoder) float(f float32) {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(f))
+	e.bytes(buf[:])
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// decoder reads values and keeps the first error that happens.
+type decoder struct {
+	r   byteReader
+	err error
+}
+
+func (d *decoder) bytes(n int) []byte {
+	b := make([]byte, n)
+	if d.err == nil {
+		_, d.err = io.ReadFull(d.r, b)
+	}
+
+	return b
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	var v uint64
+	v, d.err = binary.ReadUvarint(d.r)
+
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	var v int64
+	v, d.err = binary.ReadVarint(d.r)
+
+	return v
+}
+
+func (d *decoder) float() float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(d.bytes(4)))
+}
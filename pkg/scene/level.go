@@ -0,0 +1,151 @@
+package scene
+
+import (
+	"fmt"
+
+	"github.com/damienfamed75/rayrem/pkg/camera"
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+	"github.com/damienfamed75/rayrem/pkg/level"
+	"github.com/damienfamed75/rayrem/pkg/physics"
+	"github.com/damienfamed75/rayrem/pkg/player"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ common.Scene = &LevelScene{}
+)
+
+// LevelScene is a scene that plays a level loaded from a level file.
+type LevelScene struct {
+	sceneManager common.SceneManager
+	player       *player.Player
+	solids       physics.Broadphase
+	camera       *camera.FollowCamera
+	level        *level.Level
+
+	objects []interface{}
+}
+
+// NewLevelScene builds the level into the solids. The player is put at the
+// level's first spawn when the scene is entered.
+func NewLevelScene(sceneManager common.SceneManager, player *player.Player, solids physics.Broadphase, lvl *level.Level) (*LevelScene, error) {
+	if _, ok := lvl.Spawn(""); !ok {
+		return nil, fmt.Errorf("level %q has no spawn", lvl.Name)
+	}
+
+	return buildLevelScene(sceneManager, player, solids, lvl)
+}
+
+// buildLevelScene builds the level into the solids without putting the player
+// in it.
+func buildLevelScene(sceneManager common.SceneManager, player *player.Player, solids physics.Broadphase, lvl *level.Level) (*LevelScene, error) {
+	l := &LevelScene{
+		sceneManager: sceneManager,
+		player:       player,
+		solids:       solids,
+		level:        lvl,
+	}
+
+	objects, err := lvl.Build(solids)
+	if err != nil {
+		return nil, fmt.Errorf("build level %q: %w", lvl.Name, err)
+	}
+
+	l.objects = append(objects, l.player)
+
+	return l, nil
+}
+
+// enter moves the player into the level's solids at the position given.
+func (l *LevelScene) enter(pos r.Vector2) {
+	l.player.SetSolids(l.solids)
+	l.player.Add(l.solids)
+
+	// Set the player's position after it's in the solids so it's kept updated.
+	l.player.SetPosition(pos.X, pos.Y)
+
+	// Create the scene camera.
+	l.camera = camera.NewFollow(l.player.Space)
+}
+
+// Enter puts the player at the level's first spawn.
+func (l *LevelScene) Enter() {
+	spawn, _ := l.level.Spawn("")
+
+	l.enter(spawn)
+	l.player.Rigidbody.SetVelocity(0, 0)
+}
+
+// Exit takes the player out of the level's solids.
+func (l *LevelScene) Exit() {
+	l.solids.Remove(l.player.Actor)
+}
+
+// Level returns the level that the scene is playing.
+func (l *LevelScene) Level() *level.Level {
+	return l.level
+}
+
+// Update takes delta time and updates objects in the scene.
+func (l *LevelScene) Update(dt float32) {
+	if input.Actions.Pressed(input.Pause) {
+		l.sceneManager.Push(common.ModePause)
+	}
+
+	// Moving platforms have to move before the player so riders get carried.
+	for _, o := range l.objects {
+		if k, ok := o.(physics.Kinematic); ok {
+			k.Update(dt)
+		}
+	}
+
+	l.player.Update(dt)
+
+	// Update the rest of the objects after the player has moved.
+	for _, o := range l.objects {
+		switch o := o.(type) {
+		case physics.Kinematic, *player.Player:
+		case interface{ Update(float32) }:
+			o.Update(dt)
+		}
+	}
+
+	l.camera.Update(l.player.Rigidbody.Position(), dt)
+}
+
+// Draw draws to the screen.
+func (l *LevelScene) Draw() {
+	r.BeginMode2D(l.camera.Interpolate(common.Alpha))
+	r.ClearBackground(r.Black)
+
+	for _, o := range l.objects {
+		switch t := o.(type) {
+		case interface{ Draw() }:
+			t.Draw()
+		case physics.Transformer:
+			r.DrawRectangleLinesEx(r.NewRectangle(
+				t.Position().X, t.Position().Y,
+				t.MaxPosition().X-t.Position().X, t.MaxPosition().Y-t.Position().Y,
+			), 1, r.Orange)
+		}
+	}
+
+	r.EndMode2D()
+}
+
+// Unload frees everything that was built for the level and empties its
+// solids. The player isn't unloaded since it's shared between scenes.
+func (l *LevelScene) Unload() {
+	for _, o := range l.objects {
+		switch o := o.(type) {
+		case *player.Player:
+		case interface{ Unload() }:
+			o.Unload()
+		}
+	}
+
+	l.objects = nil
+	l.solids.Clear()
+}
@@ -2,10 +2,12 @@ package scene
 
 import (
 	"fmt"
+	"log"
 	"strconv"
 
 	"github.com/damienfamed75/rayrem/pkg/camera"
 	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
 
 	r "github.com/lachee/raylib-goplus/raylib"
 )
@@ -23,6 +25,15 @@ type Menu struct {
 	lastFullscreen bool
 	fullscreen     bool
 	vol            float32
+
+	// controls are the controls on the controls page that haven't been
+	// applied yet.
+	controls map[string]common.Control
+	// rebinding is the action that's waiting for a key to be pressed.
+	rebinding string
+	// overlay is true when the menu is only the settings, shown on top of
+	// another scene, which is gone back to when the settings are closed.
+	overlay bool
 }
 
 // NewMenu creates and sets up settings in the menu.
@@ -63,12 +74,59 @@ func NewMenu(sceneManager common.SceneManager) *Menu {
 	return m
 }
 
-// Update doesn't really do much in the main menu.
-func (m *Menu) Update(dt float32) {}
+// NewSettings creates a menu that only has the settings, which is removed
+// from the scene manager once the settings are closed.
+func NewSettings(sceneManager common.SceneManager) *Menu {
+	m := NewMenu(sceneManager)
+	m.overlay = true
+	m.states["settings"] = true
+
+	return m
+}
+
+// closeSettings hides the settings, or removes the menu if it only has the
+// settings.
+func (m *Menu) closeSettings() {
+	m.states["settings"] = false
+	m.closeControls()
+
+	if m.overlay {
+		m.sceneManager.Pop()
+	}
+}
+
+// Update lets the menu be used without a mouse. Back closes the settings and
+// confirm starts the game from the main page.
+func (m *Menu) Update(dt float32) {
+	// The next key pressed is being bound, so it can't be used for anything.
+	if m.rebinding != "" {
+		return
+	}
+
+	if m.states["controls"] {
+		if input.Actions.Pressed(input.Back) {
+			m.closeControls()
+		}
+
+		return
+	}
+
+	if m.states["settings"] {
+		if input.Actions.Pressed(input.Back) {
+			m.closeSettings()
+		}
+
+		return
+	}
+
+	if input.Actions.Pressed(input.Confirm) {
+		m.sceneManager.SetScene(common.ModeTesting)
+	}
+}
 
 // Draw draws all the buttons and stuff.
 func (m *Menu) Draw() {
-	r.BeginMode2D(m.camera.Camera2D)
+	r.BeginMode2D(m.camera.Interpolate(common.Alpha))
 	r.ClearBackground(r.White)
 
 	// If the settings window is not showing.
@@ -99,7 +157,15 @@ func (m *Menu) Draw() {
 			float32(r.GetScreenWidth()-(r.GetScreenWidth()/4)),
 			float32(r.GetScreenHeight()-(r.GetScreenHeight()/4)),
 		), "settings") {
-			m.states["settings"] = false
+			m.closeSettings()
+		}
+
+		// The controls page takes the place of the other settings.
+		if m.states["controls"] {
+			m.drawControls(pos)
+			r.EndMode2D()
+
+			return
 		}
 
 		// Apply button
@@ -126,7 +192,9 @@ func (m *Menu) Draw() {
 			common.PublicConfig.Set("screen.fullscreen", m.fullscreen)
 
 			// Save the configuration file on disk.
-			common.SavePublicConfig()
+			if err := common.SavePublicConfig(); err != nil {
+				log.Printf("save config: %v", err)
+			}
 		}
 
 		// Volume slider.
@@ -142,6 +210,11 @@ func (m *Menu) Draw() {
 			m.fullscreen = newFullscreen
 		}
 
+		// Controls button.
+		if r.GuiButton(r.NewRectangle(pos.X-50, pos.Y+200, 100, 20), "Controls") {
+			m.openControls()
+		}
+
 		// Resolution drop down menu. (THIS SHOULD ALWAYS BE AT THE BOTTOM OF SETTINGS)
 		if choose, num := r.GuiDropdownBox(r.NewRectangle(pos.X-100, pos.Y+100, 100, 20), m.getScreenResolutions(), m.actives["drop"], m.states["drop"]); choose {
 			m.states["drop"] = !m.states["drop"]
@@ -153,6 +226,14 @@ func (m *Menu) Draw() {
 	r.EndMode2D()
 }
 
+// Enter doesn't do anything since the menu is made again every time.
+func (m *Menu) Enter() {}
+
+// Exit stops any control from being rebound.
+func (m *Menu) Exit() {
+	m.closeControls()
+}
+
 // Unload doesn't do much.
 func (m *Menu) Unload() {
 
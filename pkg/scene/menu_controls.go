@@ -0,0 +1,126 @@
+package scene
+
+import (
+	"log"
+	"strings"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// openControls shows the controls page with the controls that are being used.
+func (m *Menu) openControls() {
+	m.states["controls"] = true
+	m.rebinding = ""
+
+	m.controls = make(map[string]common.Control, len(common.ControlActions))
+	for _, a := range common.ControlActions {
+		m.controls[a], _ = common.Controls.Get(a)
+	}
+}
+
+// closeControls goes back to the settings without applying any changes.
+func (m *Menu) closeControls() {
+	m.states["controls"] = false
+	m.rebinding = ""
+}
+
+// applyControls saves the controls and rebinds the actions to them.
+func (m *Menu) applyControls() {
+	for a, c := range m.controls {
+		if err := common.SetControl(a, c); err != nil {
+			log.Printf("set control: %v", err)
+		}
+	}
+
+	if err := common.SavePublicConfig(); err != nil {
+		log.Printf("save config: %v", err)
+		return
+	}
+
+	input.Load()
+
+	m.closeControls()
+}
+
+// conflicts returns the other actions that are bound to the same control as
+// the action given.
+func (m *Menu) conflicts(action string) []string {
+	var others []string
+	for _, a := range common.ControlActions {
+		if a != action && m.controls[a] == m.controls[action] {
+			others = append(others, a)
+		}
+	}
+
+	return others
+}
+
+// captureControl binds the action being rebound to the first key or gamepad
+// button that's pressed.
+func (m *Menu) captureControl() {
+	for _, c := range common.NamedControls() {
+		var pressed bool
+		if c.Gamepad {
+			pressed = r.IsGamepadAvailable(r.GamepadPlayer1) &&
+				r.IsGamepadButtonPressed(r.GamepadPlayer1, c.Button)
+		} else {
+			pressed = r.IsKeyPressed(c.Key)
+		}
+
+		if pressed {
+			m.controls[m.rebinding] = c
+			m.rebinding = ""
+
+			return
+		}
+	}
+}
+
+// drawControls draws the controls page inside of the settings window.
+func (m *Menu) drawControls(pos r.Rectangle) {
+	if m.rebinding != "" {
+		m.captureControl()
+	}
+
+	for i, a := range common.ControlActions {
+		y := pos.Y + 40 + float32(i)*25
+
+		r.GuiLabel(r.NewRectangle(pos.X-150, y, 100, 20), a)
+
+		text := m.controls[a].String()
+		if m.rebinding == a {
+			text = "press a key..."
+		}
+
+		// Clicking the action that's being rebound cancels it.
+		if r.GuiButton(r.NewRectangle(pos.X-40, y, 100, 20), text) {
+			if m.rebinding == a {
+				m.rebinding = ""
+			} else {
+				m.rebinding = a
+			}
+		}
+
+		if others := m.conflicts(a); len(others) > 0 {
+			r.DrawText("also "+strings.Join(others, ", "), int(pos.X+70), int(y+5), 10, r.Red)
+		}
+	}
+
+	bottom := float32(r.GetScreenHeight() - 175)
+
+	if r.GuiButton(r.NewRectangle(pos.X-170, bottom, 100, 20), "Defaults") {
+		m.controls = common.DefaultControls()
+		m.rebinding = ""
+	}
+
+	if r.GuiButton(r.NewRectangle(pos.X-50, bottom, 100, 20), "Apply") {
+		m.applyControls()
+	}
+
+	if r.GuiButton(r.NewRectangle(pos.X+70, bottom, 100, 20), "Back") {
+		m.closeControls()
+	}
+}
@@ -0,0 +1,102 @@
+package scene
+
+import (
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/input"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ common.Scene   = &Pause{}
+	_ common.Overlay = &Pause{}
+)
+
+// pauseButtons are the buttons of the pause menu from top to bottom.
+var pauseButtons = []string{"resume", "settings", "quit to menu"}
+
+// Pause is the pause menu, which is shown on top of the level that it
+// paused. It's only used with actions, not the mouse, so that everything it
+// does is in replays.
+type Pause struct {
+	sceneManager common.SceneManager
+	// selected is the index of the button that confirm presses.
+	selected int
+}
+
+// NewPause creates the pause menu.
+func NewPause(sceneManager common.SceneManager) *Pause {
+	return &Pause{
+		sceneManager: sceneManager,
+	}
+}
+
+// UpdateBelow is false so the level is frozen while it's paused.
+func (p *Pause) UpdateBelow() bool {
+	return false
+}
+
+// DrawBelow is true so the level can still be seen behind the menu.
+func (p *Pause) DrawBelow() bool {
+	return true
+}
+
+// Enter selects the first button.
+func (p *Pause) Enter() {
+	p.selected = 0
+}
+
+// Update moves the selection with jump and down, and presses the selected
+// button with confirm. Pause or back resumes the level.
+func (p *Pause) Update(dt float32) {
+	switch {
+	case input.Actions.Pressed(input.Pause) || input.Actions.Pressed(input.Back):
+		p.sceneManager.Pop()
+	case input.Actions.Pressed(input.Jump):
+		p.selected = (p.selected + len(pauseButtons) - 1) % len(pauseButtons)
+	case input.Actions.Pressed(input.Down):
+		p.selected = (p.selected + 1) % len(pauseButtons)
+	case input.Actions.Pressed(input.Confirm):
+		p.press(pauseButtons[p.selected])
+	}
+}
+
+// press does what the button says.
+func (p *Pause) press(button string) {
+	switch button {
+	case "resume":
+		p.sceneManager.Pop()
+	case "settings":
+		p.sceneManager.Push(common.ModeSettings)
+	case "quit to menu":
+		p.sceneManager.SetScene(common.ModeMainMenu)
+	}
+}
+
+// Draw darkens the level and draws the buttons with the selected one
+// highlighted.
+func (p *Pause) Draw() {
+	width, height := r.GetScreenWidth(), r.GetScreenHeight()
+
+	r.DrawRectangle(0, 0, width, height, r.Black.Lerp(r.Transparent, 0.4))
+	r.DrawText("paused", width/2-r.MeasureText("paused", 40)/2, height/4, 40, r.White)
+
+	for i, button := range pauseButtons {
+		rect := r.NewRectangle(float32(width/2)-150, float32(height/4+80+i*80), 300, 60)
+
+		color := r.LightGray
+		if i == p.selected {
+			color = r.White
+			r.DrawRectangleRec(rect, r.DarkGray)
+		}
+
+		r.DrawRectangleLinesEx(rect, 2, color)
+		r.DrawText(button, int(rect.X+rect.Width/2)-r.MeasureText(button, 20)/2, int(rect.Y)+20, 20, color)
+	}
+}
+
+// Exit doesn't do anything.
+func (p *Pause) Exit() {}
+
+// Unload doesn't do anything.
+func (p *Pause) Unload() {}
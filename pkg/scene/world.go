@@ -0,0 +1,156 @@
+package scene
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/level"
+	"github.com/damienfamed75/rayrem/pkg/physics"
+	"github.com/damienfamed75/rayrem/pkg/player"
+	"github.com/damienfamed75/rayrem/pkg/transition"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ common.Scene = &WorldScene{}
+)
+
+// WorldScene plays the levels of a world, which are linked to each other by
+// where they are in the world. Every level is a LevelScene with its own
+// solids, and the player goes from one to another by walking out of a level
+// into one of its neighbours behind a transition.
+type WorldScene struct {
+	player *player.Player
+	levels map[string]*LevelScene
+	// transition covers the screen while the player goes to another level.
+	transition *transition.Transition
+	// first is the level that the player starts in and current is the level
+	// that the player is in.
+	first   *LevelScene
+	current *LevelScene
+}
+
+// NewWorldScene builds every level with solids from newSolids. The player is
+// put at the first spawn of the first level when the scene is entered.
+func NewWorldScene(sceneManager common.SceneManager, player *player.Player, levels []*level.Level, newSolids func() physics.Broadphase) (*WorldScene, error) {
+	t, err := transition.Load(common.Config.Transitions.Room)
+	if err != nil {
+		return nil, fmt.Errorf("room transition: %w", err)
+	}
+
+	w := &WorldScene{
+		player:     player,
+		levels:     make(map[string]*LevelScene, len(levels)),
+		transition: t,
+	}
+
+	for _, lvl := range levels {
+		l, err := buildLevelScene(sceneManager, player, newSolids(), lvl)
+		if err != nil {
+			return nil, err
+		}
+
+		w.levels[lvl.Name] = l
+	}
+
+	for _, lvl := range levels {
+		for _, n := range lvl.Neighbours {
+			if _, ok := w.levels[n]; !ok {
+				return nil, fmt.Errorf("level %q: no neighbour named %q", lvl.Name, n)
+			}
+		}
+	}
+
+	if _, ok := levels[0].Spawn(""); !ok {
+		return nil, fmt.Errorf("level %q has no spawn", levels[0].Name)
+	}
+
+	w.first = w.levels[levels[0].Name]
+
+	return w, nil
+}
+
+// Enter puts the player at the first spawn of the first level.
+func (w *WorldScene) Enter() {
+	w.current = w.first
+	w.current.Enter()
+}
+
+// Exit takes the player out of the level that they're in.
+func (w *WorldScene) Exit() {
+	w.current.Exit()
+}
+
+// Level returns the level that the player is in.
+func (w *WorldScene) Level() *level.Level {
+	return w.current.level
+}
+
+// Update updates the level that the player is in, and then moves the player
+// to another level if they left it. The world is frozen while the player is
+// going to another level.
+func (w *WorldScene) Update(dt float32) {
+	if w.transition.Running() {
+		if err := w.transition.Update(dt); err != nil {
+			log.Printf("room transition: %v", err)
+		}
+
+		return
+	}
+
+	w.current.Update(dt)
+	w.travel()
+}
+
+// travel moves the player into the neighbour that they walked into once the
+// screen is covered. Levels without a size in the world can't be left.
+func (w *WorldScene) travel() {
+	bounds := w.current.level.World
+	if bounds.W == 0 || bounds.H == 0 {
+		return
+	}
+
+	// The player has left the level once their center is outside of it.
+	pos := w.player.Rigidbody.Position()
+	center := pos.Add(r.NewVector2(w.player.Rigidbody.Width()/2, w.player.Rigidbody.Height()/2))
+	if (level.Rect{W: bounds.W, H: bounds.H}).Contains(center) {
+		return
+	}
+
+	offset := r.NewVector2(bounds.X, bounds.Y)
+	world := center.Add(offset)
+
+	for _, n := range w.current.level.Neighbours {
+		next := w.levels[n]
+		if !next.level.World.Contains(world) {
+			continue
+		}
+
+		// Positions in levels are relative to the level's corner.
+		pos = pos.Add(offset).Subtract(r.NewVector2(next.level.World.X, next.level.World.Y))
+
+		w.transition.Start(transition.Steps(func() error {
+			w.current = next
+			w.current.enter(pos)
+
+			return nil
+		}))
+
+		return
+	}
+}
+
+// Draw draws the level that the player is in.
+func (w *WorldScene) Draw() {
+	w.current.Draw()
+	w.transition.Draw()
+}
+
+// Unload unloads every level.
+func (w *WorldScene) Unload() {
+	for _, l := range w.levels {
+		l.Unload()
+	}
+}
@@ -0,0 +1,81 @@
+package transition
+
+import (
+	"math"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+var (
+	_ Effect = Fade{}
+	_ Effect = Wipe{}
+	_ Effect = Iris{}
+)
+
+// Effect is how a transition covers the screen.
+type Effect interface {
+	// Draw covers the amount of the screen given, from 0 for none of it to
+	// 1 for all of it.
+	Draw(amount float32)
+}
+
+// Fade fades the screen to a color.
+type Fade struct {
+	Color r.Color
+}
+
+// Draw draws the color over the screen with the amount as its opacity.
+func (f Fade) Draw(amount float32) {
+	r.DrawRectangle(0, 0, r.GetScreenWidth(), r.GetScreenHeight(), f.Color.Fade(amount))
+}
+
+// Wipe slides a color over the screen from one side to the other.
+type Wipe struct {
+	Color r.Color
+	// Direction is the way that the color slides, which is right if it isn't
+	// set.
+	Direction common.Direction
+}
+
+// Draw draws the color over the amount of the screen from the side that it
+// slides in from.
+func (w Wipe) Draw(amount float32) {
+	width, height := r.GetScreenWidth(), r.GetScreenHeight()
+	covered := int(float32(width) * amount)
+
+	x := 0
+	if w.Direction == common.Left {
+		x = width - covered
+	}
+
+	r.DrawRectangle(x, 0, covered, height, w.Color)
+}
+
+// Iris closes a circle of the screen down to a point.
+type Iris struct {
+	Color r.Color
+	// Center is where the circle closes to on the screen, which is the middle
+	// of the screen if it isn't set.
+	Center r.Vector2
+}
+
+// Draw draws the color everywhere outside of the circle, which gets smaller
+// as the amount goes up.
+func (i Iris) Draw(amount float32) {
+	width, height := float32(r.GetScreenWidth()), float32(r.GetScreenHeight())
+
+	center := i.Center
+	if center.X == 0 && center.Y == 0 {
+		center = r.NewVector2(width/2, height/2)
+	}
+
+	// The circle starts big enough to show the corner furthest from the
+	// center.
+	dx := float32(math.Max(float64(center.X), float64(width-center.X)))
+	dy := float32(math.Max(float64(center.Y), float64(height-center.Y)))
+	outer := float32(math.Hypot(float64(dx), float64(dy)))
+
+	r.DrawRing(center, outer*(1-amount), outer+1, 0, 360, 64, i.Color)
+}
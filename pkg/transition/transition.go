@@ -0,0 +1,216 @@
+// Package transition covers the screen while something changes behind it,
+// like the scene or the level of a world that the player is in. Work can be
+// done while the screen is covered, such as loading the next level, and its
+// progress is shown if it takes a while.
+//
+// Transitions are updated with the game's fixed updates, so they always take
+// the same amount of updates no matter how fast the game is drawn.
+package transition
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/damienfamed75/rayrem/pkg/common"
+	"github.com/damienfamed75/rayrem/pkg/ease"
+
+	r "github.com/lachee/raylib-goplus/raylib"
+)
+
+// Task is work that's done while the screen is covered. It's called once
+// every update until it's done, and returns how much of the work is done
+// from 0 to 1 so that the progress can be shown.
+type Task func() (progress float32, done bool, err error)
+
+// Steps returns a task that calls each step on its own update, so the
+// progress is shown between the steps.
+func Steps(steps ...func() error) Task {
+	var i int
+	return func() (float32, bool, error) {
+		if len(steps) == 0 {
+			return 1, true, nil
+		}
+
+		if i < len(steps) {
+			if err := steps[i](); err != nil {
+				return 1, true, err
+			}
+			i++
+		}
+
+		return float32(i) / float32(len(steps)), i >= len(steps), nil
+	}
+}
+
+// state is which part of the transition is running.
+type state int
+
+const (
+	idle state = iota
+	covering
+	working
+	uncovering
+)
+
+// Transition covers the screen with an effect, does its task, and then
+// uncovers the screen again.
+type Transition struct {
+	Effect Effect
+	// Duration is how many seconds covering and uncovering each take.
+	Duration float32
+	// Ease changes how fast the screen is covered over time.
+	Ease ease.Func
+	// ProgressDelay is how many seconds the task can take before its progress
+	// is shown.
+	ProgressDelay float32
+
+	state   state
+	elapsed float32
+
+	task     Task
+	progress float32
+	// started is when the task started, which is in real time instead of
+	// updates since a slow task holds up the updates.
+	started time.Time
+}
+
+// New creates a transition with the effect that takes the duration given to
+// cover and uncover the screen.
+func New(effect Effect, duration float32, f ease.Func) *Transition {
+	return &Transition{
+		Effect:        effect,
+		Duration:      duration,
+		Ease:          f,
+		ProgressDelay: 0.5,
+	}
+}
+
+// Load creates a transition from its config. Effects are drawn in black.
+func Load(c common.TransitionConfig) (*Transition, error) {
+	var effect Effect
+
+	switch c.Effect {
+	case "fade":
+		effect = Fade{Color: r.Black}
+	case "wipe":
+		effect = Wipe{Color: r.Black}
+	case "iris":
+		effect = Iris{Color: r.Black}
+	default:
+		return nil, fmt.Errorf("unknown effect %q", c.Effect)
+	}
+
+	f, ok := ease.Named(c.Ease)
+	if !ok {
+		return nil, fmt.Errorf("unknown ease %q", c.Ease)
+	}
+
+	t := New(effect, c.Duration, f)
+	t.ProgressDelay = c.ProgressDelay
+
+	return t, nil
+}
+
+// Start covers the screen and does the task once it's covered. Nothing
+// happens if the transition is already running.
+func (t *Transition) Start(task Task) {
+	if t.Running() {
+		return
+	}
+
+	t.state = covering
+	t.elapsed = 0
+	t.task = task
+	t.progress = 0
+}
+
+// Running returns if the transition has started and the screen hasn't been
+// uncovered yet.
+func (t *Transition) Running() bool {
+	return t.state != idle
+}
+
+// Working returns if the screen is covered and the task is being done.
+func (t *Transition) Working() bool {
+	return t.state == working
+}
+
+// Update covers or uncovers the screen, or does a part of the task while
+// it's covered. Errors from the task are returned once and the screen is
+// uncovered anyway.
+func (t *Transition) Update(dt float32) error {
+	switch t.state {
+	case covering:
+		t.elapsed += dt
+		if t.elapsed >= t.Duration {
+			t.state = working
+			t.started = time.Now()
+		}
+	case working:
+		progress, done, err := t.task()
+		t.progress = progress
+
+		if done || err != nil {
+			t.state = uncovering
+			t.elapsed = 0
+			t.task = nil
+		}
+
+		return err
+	case uncovering:
+		t.elapsed += dt
+		if t.elapsed >= t.Duration {
+			t.state = idle
+		}
+	}
+
+	return nil
+}
+
+// amount returns how much of the screen is covered.
+func (t *Transition) amount() float32 {
+	var linear float32
+	switch {
+	case t.state == idle:
+		return 0
+	case t.state == working || t.Duration <= 0:
+		return 1
+	case t.state == covering:
+		linear = t.elapsed / t.Duration
+	case t.state == uncovering:
+		linear = 1 - t.elapsed/t.Duration
+	}
+
+	if linear < 0 {
+		linear = 0
+	} else if linear > 1 {
+		linear = 1
+	}
+
+	if t.Ease == nil {
+		return linear
+	}
+
+	return t.Ease(linear)
+}
+
+// Draw draws the effect over the screen, and the progress of the task if
+// it's been working for longer than the progress delay.
+func (t *Transition) Draw() {
+	if !t.Running() {
+		return
+	}
+
+	t.Effect.Draw(t.amount())
+
+	if !t.Working() || time.Since(t.started).Seconds() < float64(t.ProgressDelay) {
+		return
+	}
+
+	width, height := float32(r.GetScreenWidth()), float32(r.GetScreenHeight())
+	bar := r.NewRectangle(width/4, height-80, width/2, 20)
+
+	r.DrawText("loading", int(bar.X), int(bar.Y)-30, 20, r.White)
+	r.DrawRectangleRec(r.NewRectangle(bar.X, bar.Y, bar.Width*t.progress, bar.Height), r.White)
+	r.DrawRectangleLinesEx(bar, 2, r.White)
+}